@@ -0,0 +1,167 @@
+package logg
+
+import (
+	stdjson "encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LevelHandler returns an http.Handler which reads and changes minimum levels
+// of the global logger and registered loggers. The logger is selected with
+// the logger query parameter, GlobalName by default. A change can be reverted
+// automatically after a timeout. Each change is written by the changed logger.
+//
+//	GET /             {"global": "INF", "api": "DBG"}
+//	GET /?logger=api  {"logger": "api", "level": "DBG"}
+//	PUT /?logger=api  {"level": "DBG", "revert": "10m"}
+func LevelHandler() http.Handler {
+	return &levelHandler{reverts: map[string]*levelRevert{}}
+}
+
+type levelHandler struct {
+	mu      sync.Mutex
+	reverts map[string]*levelRevert // pending reverts by logger name
+}
+
+// levelRevert restores the level which was set before the first change.
+type levelRevert struct {
+	timer *time.Timer
+	level Level
+}
+
+type levelRequest struct {
+	Level  *Level `json:"level"`
+	Revert string `json:"revert,omitempty"` // duration, e.g. 10m
+}
+
+type levelResponse struct {
+	Logger   string     `json:"logger"`
+	Level    Level      `json:"level"`
+	Previous *Level     `json:"previous,omitempty"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("logger")
+
+	switch r.Method {
+	case http.MethodGet:
+		if name == "" {
+			levels := map[string]Level{}
+			for _, n := range LoggerNames() {
+				if l, ok := Logger(n); ok {
+					levels[n] = l.Level()
+				}
+			}
+			writeJSON(w, http.StatusOK, levels)
+			return
+		}
+
+		l, ok := Logger(name)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown logger "+name)
+			return
+		}
+		writeJSON(w, http.StatusOK, levelResponse{Logger: name, Level: l.Level()})
+
+	case http.MethodPut, http.MethodPost:
+		if name == "" {
+			name = GlobalName
+		}
+		l, ok := Logger(name)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown logger "+name)
+			return
+		}
+
+		var req levelRequest
+		if err := stdjson.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Level == nil {
+			writeJSONError(w, http.StatusBadRequest, "level is required")
+			return
+		}
+
+		var revert time.Duration
+		if req.Revert != "" {
+			d, err := time.ParseDuration(req.Revert)
+			if err != nil || d <= 0 {
+				writeJSONError(w, http.StatusBadRequest, "invalid revert duration "+req.Revert)
+				return
+			}
+			revert = d
+		}
+
+		writeJSON(w, http.StatusOK, h.setLevel(name, l, *req.Level, revert, r.RemoteAddr))
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// setLevel changes the level and schedules the revert if the timeout is set.
+func (h *levelHandler) setLevel(name string, l *Logg, level Level, revert time.Duration, remote string) levelResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous := l.Level()
+	l.MinLevel(level)
+
+	kv := []interface{}{String("logger", name), String("from", previous.String()), String("to", level.String())}
+	if remote != "" {
+		kv = append(kv, String("remote", remote))
+	}
+
+	// a pending revert restores the level which was set before the first change
+	original := previous
+	if pending, ok := h.reverts[name]; ok {
+		pending.timer.Stop()
+		original = pending.level
+		delete(h.reverts, name)
+	}
+
+	resp := levelResponse{Logger: name, Level: level, Previous: &previous}
+	if revert != 0 {
+		at := time.Now().Add(revert)
+		resp.RevertAt = &at
+		kv = append(kv, Duration("revert", revert))
+
+		rv := &levelRevert{level: original}
+		rv.timer = time.AfterFunc(revert, func() { h.revert(name, l, rv) })
+		h.reverts[name] = rv
+	}
+
+	l.write(1, Empty, []byte("log level changed"), kv...)
+
+	return resp
+}
+
+// revert restores the level if the revert is still pending.
+func (h *levelHandler) revert(name string, l *Logg, rv *levelRevert) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.reverts[name] != rv {
+		return
+	}
+	delete(h.reverts, name)
+
+	previous := l.Level()
+	l.MinLevel(rv.level)
+	l.write(1, Empty, []byte("log level reverted"),
+		String("logger", name), String("from", previous.String()), String("to", rv.level.String()))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = stdjson.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
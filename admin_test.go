@@ -0,0 +1,160 @@
+package logg
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func doLevelRequest(t *testing.T, h http.Handler, method, target, body string) (int, map[string]interface{}) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+	resp := map[string]interface{}{}
+	if err := stdjson.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid json response %q: %v", w.Body.String(), err)
+	}
+
+	return w.Code, resp
+}
+
+func TestLevelHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+	RegisterLogger("api", logger)
+	defer RegisterLogger("api", nil)
+
+	h := LevelHandler()
+
+	code, resp := doLevelRequest(t, h, http.MethodGet, "/?logger=api", "")
+	if code != http.StatusOK || resp["logger"] != "api" || resp["level"] != "INF" {
+		t.Errorf("wrong response: %d %v", code, resp)
+	}
+
+	code, resp = doLevelRequest(t, h, http.MethodPut, "/?logger=api", `{"level": "debug"}`)
+	if code != http.StatusOK || resp["level"] != "DBG" || resp["previous"] != "INF" {
+		t.Errorf("wrong response: %d %v", code, resp)
+	}
+	if logger.Level() != Debug {
+		t.Errorf("level must be changed to debug, received: %s", logger.Level())
+	}
+	if output := readFromBuffer(buf); !strings.HasPrefix(output, "log level changed logger=api from=INF to=DBG remote=") {
+		t.Errorf("wrong audit message: %s", output)
+	}
+
+	code, resp = doLevelRequest(t, h, http.MethodGet, "/", "")
+	if code != http.StatusOK || resp["api"] != "DBG" || resp[GlobalName] == nil {
+		t.Errorf("wrong response: %d %v", code, resp)
+	}
+
+	tests := map[string]struct {
+		method string
+		target string
+		body   string
+		code   int
+	}{
+		"unknown logger":     {method: http.MethodGet, target: "/?logger=missing", code: http.StatusNotFound},
+		"invalid json":       {method: http.MethodPut, target: "/?logger=api", body: `{`, code: http.StatusBadRequest},
+		"missing level":      {method: http.MethodPut, target: "/?logger=api", body: `{}`, code: http.StatusBadRequest},
+		"unknown level":      {method: http.MethodPut, target: "/?logger=api", body: `{"level": "loud"}`, code: http.StatusBadRequest},
+		"invalid revert":     {method: http.MethodPut, target: "/?logger=api", body: `{"level": "DBG", "revert": "soon"}`, code: http.StatusBadRequest},
+		"method not allowed": {method: http.MethodDelete, target: "/", code: http.StatusMethodNotAllowed},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, resp := doLevelRequest(t, h, tc.method, tc.target, tc.body)
+			if code != tc.code || resp["error"] == nil {
+				t.Errorf("wrong response: %d %v", code, resp)
+			}
+		})
+	}
+}
+
+func TestLevelHandler_revert(t *testing.T) {
+	out := newGateWriter()
+	close(out.gate)
+	logger := New(out)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+	RegisterLogger("worker", logger)
+	defer RegisterLogger("worker", nil)
+
+	h := LevelHandler()
+
+	code, resp := doLevelRequest(t, h, http.MethodPut, "/?logger=worker", `{"level": "TRC", "revert": "1h"}`)
+	if code != http.StatusOK || resp["revert_at"] == nil {
+		t.Errorf("wrong response: %d %v", code, resp)
+	}
+
+	// the second change reverts to the level before the first change
+	doLevelRequest(t, h, http.MethodPut, "/?logger=worker", `{"level": "DBG", "revert": "20ms"}`)
+	if logger.Level() != Debug {
+		t.Errorf("level must be changed to debug, received: %s", logger.Level())
+	}
+
+	const reverted = "log level reverted logger=worker from=DBG to=INF"
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(out.String(), reverted) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if !strings.Contains(out.String(), reverted) {
+		t.Errorf("revert must be logged: %s", out.String())
+	}
+	if logger.Level() != Info {
+		t.Errorf("level must be reverted to info, received: %s", logger.Level())
+	}
+}
+
+func TestLevelHandler_derived(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+	RegisterLogger("requests", logger)
+	defer RegisterLogger("requests", nil)
+
+	l, _ := Logger("requests")
+	child := l.With(String("request_id", "1"))
+	slogger := slog.New(NewSlogHandler(l)).With("request_id", "2")
+
+	doLevelRequest(t, LevelHandler(), http.MethodPut, "/?logger=requests", `{"level": "DBG"}`)
+	buf.Reset()
+
+	child.Debug("test")
+	slogger.Debug("test")
+	expected := "DBG test request_id=1\nDBG test request_id=2\n"
+	if output := buf.String(); output != expected {
+		t.Errorf("derived loggers must use the changed level. Expected: %q, received: %q", expected, output)
+	}
+}
+
+func TestRegisterLogger(t *testing.T) {
+	logger := New(nil)
+	RegisterLogger("db", logger)
+
+	if l, ok := Logger("db"); !ok || l != logger {
+		t.Error("registered logger must be returned")
+	}
+	if l, ok := Logger(GlobalName); !ok || l != logg.Load() {
+		t.Error("global logger must be returned")
+	}
+	if names := LoggerNames(); names[0] != GlobalName {
+		t.Errorf("global logger must be the first: %v", names)
+	}
+
+	RegisterLogger("db", nil)
+	if _, ok := Logger("db"); ok {
+		t.Error("logger must be removed")
+	}
+}
@@ -0,0 +1,205 @@
+package logg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Overflow defines what an AsyncWriter does when its buffer is full.
+type Overflow int
+
+// Overflow policies
+const (
+	Block      Overflow = iota // wait for free space in the buffer
+	DropNewest                 // drop the line which is written
+	DropOldest                 // drop the oldest line in the buffer
+)
+
+// DefaultAsyncSize is a default number of lines in the AsyncWriter buffer.
+const DefaultAsyncSize = 1024
+
+// AsyncConfig defines the AsyncWriter buffer.
+type AsyncConfig struct {
+	Size     int      // maximum number of buffered lines, DefaultAsyncSize by default
+	Overflow Overflow // policy for a full buffer, Block by default
+}
+
+// AsyncWriter is an io.Writer which copies lines to a bounded ring buffer
+// and writes them to the underlying writer in a background goroutine.
+// Each Write call is written to the underlying writer by a separate Write call.
+//
+//	w := logg.NewAsyncWriter(os.Stdout, logg.AsyncConfig{Overflow: logg.DropOldest})
+//	defer w.Close()
+//	log := logg.New(w)
+type AsyncWriter struct {
+	w        io.Writer
+	overflow Overflow
+	dropped  uint64
+
+	wmu sync.Mutex // serializes writes and flushes of the underlying writer
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	drained  *sync.Cond
+
+	lines   [][]byte // ring buffer, buffers of slots are reused
+	head    int      // index of the oldest line
+	n       int      // number of buffered lines
+	spare   []byte   // buffer swapped with a slot which is written
+	writing bool     // a line is being written to the underlying writer
+	closed  bool
+
+	done chan struct{}
+}
+
+// NewAsyncWriter creates a writer and starts writing to w in the background.
+func NewAsyncWriter(w io.Writer, cfg AsyncConfig) *AsyncWriter {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultAsyncSize
+	}
+
+	a := &AsyncWriter{
+		w:        w,
+		overflow: cfg.Overflow,
+		lines:    make([][]byte, cfg.Size),
+		done:     make(chan struct{}),
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+	a.drained = sync.NewCond(&a.mu)
+
+	go a.run()
+
+	return a
+}
+
+// Write copies b to the buffer. Depending on the overflow policy it waits
+// for free space or drops a line if the buffer is full.
+// Dropped lines are not reported as errors, see Dropped.
+func (a *AsyncWriter) Write(b []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return 0, os.ErrClosed
+	}
+
+	if a.n == len(a.lines) {
+		switch a.overflow {
+		case DropNewest:
+			atomic.AddUint64(&a.dropped, 1)
+			return len(b), nil
+		case DropOldest:
+			a.head = (a.head + 1) % len(a.lines)
+			a.n--
+			atomic.AddUint64(&a.dropped, 1)
+		default:
+			for a.n == len(a.lines) && !a.closed {
+				a.notFull.Wait()
+			}
+			if a.closed {
+				return 0, os.ErrClosed
+			}
+		}
+	}
+
+	i := (a.head + a.n) % len(a.lines)
+	a.lines[i] = append(a.lines[i][:0], b...)
+	a.n++
+	a.notEmpty.Signal()
+
+	return len(b), nil
+}
+
+// Dropped returns the number of lines dropped because of a full buffer.
+func (a *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush waits until buffered lines are written and flushes the underlying writer.
+func (a *AsyncWriter) Flush() error {
+	a.mu.Lock()
+	a.wait()
+	a.mu.Unlock()
+
+	return a.flush()
+}
+
+// Close writes buffered lines, flushes the underlying writer and stops
+// the background goroutine. Writes after Close return an error.
+// The underlying writer is not closed.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return os.ErrClosed
+	}
+	a.closed = true
+	a.notEmpty.Signal()
+	a.notFull.Broadcast()
+	a.mu.Unlock()
+
+	<-a.done
+
+	return a.flush()
+}
+
+// flush flushes the underlying writer, lines written in the meantime
+// by the background goroutine wait for it.
+func (a *AsyncWriter) flush() error {
+	a.wmu.Lock()
+	defer a.wmu.Unlock()
+
+	return flush(a.w)
+}
+
+// wait waits until the buffer is empty. Must be called under the lock.
+func (a *AsyncWriter) wait() {
+	for a.n != 0 || a.writing {
+		a.drained.Wait()
+	}
+}
+
+// run writes buffered lines to the underlying writer until the writer is closed.
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for {
+		for a.n == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if a.n == 0 {
+			return
+		}
+
+		// swap the slot with the spare buffer, so the line
+		// can be written without the lock
+		b := a.lines[a.head]
+		a.lines[a.head] = a.spare
+		a.head = (a.head + 1) % len(a.lines)
+		a.n--
+		a.writing = true
+		a.notFull.Signal()
+		a.mu.Unlock()
+
+		a.wmu.Lock()
+		if err := write(a.w, b); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logg: could not write message: %v\n", err)
+		}
+		a.wmu.Unlock()
+
+		a.mu.Lock()
+		a.spare = b[:0]
+		a.writing = false
+		if a.n == 0 {
+			a.drained.Broadcast()
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package logg
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// gateWriter blocks writes until the gate is opened.
+type gateWriter struct {
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	flushed bool
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(b []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(b)
+}
+
+func (w *gateWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushed = true
+	return nil
+}
+
+func (w *gateWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewAsyncWriter(buf, AsyncConfig{Size: 4})
+
+	var expected string
+	for i := 0; i < 100; i++ {
+		line := strconv.Itoa(i) + "\n"
+		expected += line
+		if n, err := w.Write([]byte(line)); err != nil || n != len(line) {
+			t.Fatalf("wrong write result: %d, %v", n, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expected {
+		t.Errorf("wrong output. Expected: %q, received: %q", expected, buf.String())
+	}
+	if _, err := w.Write([]byte("test")); err != os.ErrClosed {
+		t.Errorf("expected closed error, received: %v", err)
+	}
+	if err := w.Close(); err != os.ErrClosed {
+		t.Errorf("expected closed error, received: %v", err)
+	}
+}
+
+func TestAsyncWriter_overflow(t *testing.T) {
+	tests := map[string]struct {
+		overflow Overflow
+		expected string
+	}{
+		"drop newest": {overflow: DropNewest, expected: "0\n1\n2\n"},
+		"drop oldest": {overflow: DropOldest, expected: "0\n4\n5\n"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gw := newGateWriter()
+			w := NewAsyncWriter(gw, AsyncConfig{Size: 2, Overflow: tc.overflow})
+
+			// the first line is taken by the background goroutine,
+			// next lines fill the buffer
+			_, _ = w.Write([]byte("0\n"))
+			<-gw.started
+			for i := 1; i < 6; i++ {
+				if _, err := w.Write([]byte(strconv.Itoa(i) + "\n")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if w.Dropped() != 3 {
+				t.Errorf("expected 3 dropped lines, received: %d", w.Dropped())
+			}
+
+			close(gw.gate)
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if output := gw.String(); output != tc.expected {
+				t.Errorf("wrong output. Expected: %q, received: %q", tc.expected, output)
+			}
+			_ = w.Close()
+		})
+	}
+}
+
+func TestAsyncWriter_block(t *testing.T) {
+	gw := newGateWriter()
+	w := NewAsyncWriter(gw, AsyncConfig{Size: 1})
+
+	_, _ = w.Write([]byte("0\n"))
+	<-gw.started
+	_, _ = w.Write([]byte("1\n"))
+
+	written := make(chan struct{})
+	go func() {
+		_, _ = w.Write([]byte("2\n"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("write must block on a full buffer")
+	default:
+	}
+
+	close(gw.gate)
+	<-written
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if output := gw.String(); output != "0\n1\n2\n" {
+		t.Errorf("wrong output: %q", output)
+	}
+	if w.Dropped() != 0 {
+		t.Errorf("unexpected dropped lines: %d", w.Dropped())
+	}
+	if !gw.flushed {
+		t.Error("underlying writer was not flushed")
+	}
+}
+
+func TestAsyncWriter_Logg(t *testing.T) {
+	gw := newGateWriter()
+	close(gw.gate)
+	w := NewAsyncWriter(gw, AsyncConfig{})
+
+	l := New(w)
+	l.SetFlags(0)
+	l.ToggleColor(false)
+	l.SetExitFunc(func(int) {})
+	l.Info("first")
+	l.Fatal("second")
+
+	if output := gw.String(); output != "INF first\nFTL second\n" {
+		t.Errorf("wrong output after fatal: %q", output)
+	}
+	_ = w.Close()
+}
+
+func TestAsyncWriter_FlushConcurrent(t *testing.T) {
+	bw := bufio.NewWriterSize(io.Discard, 16)
+	w := NewAsyncWriter(bw, AsyncConfig{Size: 8})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				_, _ = w.Write([]byte("line which is longer than the buffer\n"))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if err := w.Flush(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package logg
 
 import (
+	"context"
 	"io/ioutil"
 	"log"
 	"testing"
@@ -86,6 +87,24 @@ func BenchmarkLogg_Write_Json(b *testing.B) {
 	}
 }
 
+func BenchmarkLogg_Write_Logfmt(b *testing.B) {
+	logger := New(ioutil.Discard)
+	logger.format = Logfmt
+
+	for name, tc := range benchmarkMessages {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, _ = logger.Write(tc)
+				}
+			})
+		})
+	}
+}
+
 func BenchmarkLogg_Print(b *testing.B) {
 	logger := New(ioutil.Discard)
 
@@ -123,3 +142,73 @@ func BenchmarkLogg_Printf(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkLogg_Infow(b *testing.B) {
+	logger := New(ioutil.Discard)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("test logging", "user", "bob", "status", "ok")
+		}
+	})
+}
+
+func BenchmarkLogg_With(b *testing.B) {
+	logger := New(ioutil.Discard).With(String("service", "api"), Int("id", 1))
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("test logging", "user", "bob")
+		}
+	})
+}
+
+func BenchmarkLogg_Async(b *testing.B) {
+	w := NewAsyncWriter(ioutil.Discard, AsyncConfig{Overflow: DropNewest})
+	defer w.Close()
+	logger := New(w)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("test logging", "user", "bob")
+		}
+	})
+}
+
+func BenchmarkLogg_Sinks(b *testing.B) {
+	logger := New(ioutil.Discard)
+	logger.AddSink(Sink{Writer: ioutil.Discard, Format: Json, MinLevel: Debug})
+	logger.AddSink(Sink{Writer: ioutil.Discard, Format: Json, MinLevel: Debug})
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("test logging", "user", "bob")
+		}
+	})
+}
+
+func BenchmarkLogg_InfoCtx(b *testing.B) {
+	logger := New(ioutil.Discard)
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.InfoCtx(ctx, "test logging", "user", "bob")
+		}
+	})
+}
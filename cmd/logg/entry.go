@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+// timeLayouts are layouts of timestamps written with logg flags in the json format.
+// The fractional second is optional when parsing.
+var timeLayouts = []struct {
+	layout string
+	flags  int
+}{
+	{layout: "2006-01-02T15:04:05Z07:00", flags: logg.Ldate | logg.Ltime},
+	{layout: "15:04:05Z07:00", flags: logg.Ltime},
+	{layout: "2006-01-02Z07:00", flags: logg.Ldate},
+}
+
+// rawValue is a json array or null, written as is.
+type rawValue string
+
+func (v rawValue) String() string { return string(v) }
+
+// parseEntry parses a json line written by logg. Time, file, line, level
+// and message keys fill the entry, other keys are fields in the line order.
+// Objects are groups.
+func parseEntry(line []byte) (*logg.Entry, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, errors.New("not a json object")
+	}
+
+	fields, err := parseObject(line)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &logg.Entry{Level: logg.Empty, Fields: fields[:0]}
+	for _, f := range fields {
+		switch v := f.Value().(type) {
+		case string:
+			switch f.Key {
+			case "time":
+				if t, flags, ok := parseTime(v); ok {
+					e.Time, e.Flags = t, e.Flags|flags
+					continue
+				}
+			case "file":
+				e.File, e.Flags = v, e.Flags|logg.Llongfile
+				continue
+			case "level":
+				if level, err := logg.ParseLevel(v); err == nil {
+					e.Level = level
+					continue
+				}
+			case "message":
+				e.Message = []byte(v)
+				continue
+			}
+		case int64:
+			if f.Key == "line" {
+				e.Line = int(v)
+				continue
+			}
+		}
+
+		e.Fields = append(e.Fields, f)
+	}
+
+	return e, nil
+}
+
+// parseObject returns keys of the json object as fields.
+func parseObject(data []byte) ([]logg.Field, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, errors.New("not a json object")
+	}
+
+	var fields []logg.Field
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := t.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		f, err := parseValue(key, raw)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("data after the json object")
+	}
+
+	return fields, nil
+}
+
+// parseValue returns a field with the json value.
+func parseValue(key string, raw json.RawMessage) (logg.Field, error) {
+	switch raw[0] {
+	case '{':
+		fields, err := parseObject(raw)
+		if err != nil {
+			return logg.Field{}, err
+		}
+		return logg.Group(key, fields...), nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return logg.Field{}, err
+		}
+		return logg.String(key, s), nil
+	case 't', 'f':
+		return logg.Bool(key, raw[0] == 't'), nil
+	case '[', 'n':
+		return logg.Any(key, rawValue(raw)), nil
+	}
+
+	s := string(raw)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return logg.Int64(key, i), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return logg.Field{}, err
+	}
+
+	return logg.Float64(key, f), nil
+}
+
+// parseTime parses a timestamp and returns flags which produce it.
+func parseTime(s string) (time.Time, int, bool) {
+	for _, l := range timeLayouts {
+		t, err := time.Parse(l.layout, s)
+		if err != nil {
+			continue
+		}
+
+		flags := l.flags
+		if strings.IndexByte(s, '.') != -1 {
+			flags |= logg.Lmicroseconds
+		}
+
+		return t, flags, true
+	}
+
+	return time.Time{}, 0, false
+}
+
+// findField returns the field with the key. Fields of groups
+// are found by dotted keys (req.method).
+func findField(fields []logg.Field, key string) (logg.Field, bool) {
+	for _, f := range fields {
+		if f.Key == key {
+			return f, true
+		}
+
+		group, ok := f.Value().([]logg.Field)
+		switch {
+		case !ok:
+		case f.Key == "":
+			if f, ok := findField(group, key); ok {
+				return f, true
+			}
+		case strings.HasPrefix(key, f.Key+"."):
+			if f, ok := findField(group, key[len(f.Key)+1:]); ok {
+				return f, true
+			}
+		}
+	}
+
+	return logg.Field{}, false
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+func Test_parseEntry(t *testing.T) {
+	e, err := parseEntry([]byte(`{"time": "2020-01-02T03:04:05.000006+02:00", "file": "api.go", "line": 10, "level": "ERR", "message": "failed", "req": {"method": "GET", "ids": [1, 2]}, "status": 500, "ratio": 0.5, "ok": false, "x": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !e.Time.Equal(time.Date(2020, 1, 2, 1, 4, 5, 6000, time.UTC)) {
+		t.Errorf("wrong time: %s", e.Time)
+	}
+	if flags := logg.Ldate | logg.Ltime | logg.Lmicroseconds | logg.Llongfile; e.Flags != flags {
+		t.Errorf("wrong flags. Expected: %b, received: %b", flags, e.Flags)
+	}
+	if e.File != "api.go" || e.Line != 10 || e.Level != logg.Error || string(e.Message) != "failed" {
+		t.Errorf("wrong entry: %s:%d %s %s", e.File, e.Line, e.Level, e.Message)
+	}
+
+	var keys []string
+	for _, f := range e.Fields {
+		keys = append(keys, f.Key)
+	}
+	if expected := []string{"req", "status", "ratio", "ok", "x"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("wrong fields order. Expected: %v, received: %v", expected, keys)
+	}
+
+	tests := map[string]string{
+		"req.method": "GET",
+		"req.ids":    "[1, 2]",
+		"status":     "500",
+		"ratio":      "0.5",
+		"ok":         "false",
+		"x":          "null",
+	}
+	for key, value := range tests {
+		f, ok := findField(e.Fields, key)
+		if !ok || string(f.AppendText(nil)) != value {
+			t.Errorf("wrong %s field. Expected: %s, received: %s", key, value, f.AppendText(nil))
+		}
+	}
+	if _, ok := findField(e.Fields, "req.path"); ok {
+		t.Error("missing field must not be found")
+	}
+}
+
+func Test_parseEntry_invalid(t *testing.T) {
+	for _, line := range []string{"", "plain text", "[1, 2]", `{"level": "INF"`, `{"a": 1} {"b": 2}`, `{"a": 1e}`} {
+		if _, err := parseEntry([]byte(line)); err == nil {
+			t.Errorf("%q must not be parsed", line)
+		}
+	}
+}
+
+func Test_parseTime(t *testing.T) {
+	tests := map[string]int{
+		"2020-01-02T03:04:05Z":        logg.Ldate | logg.Ltime,
+		"2020-01-02T03:04:05.000001Z": logg.Ldate | logg.Ltime | logg.Lmicroseconds,
+		"03:04:05+03:00":              logg.Ltime,
+		"2020-01-02Z":                 logg.Ldate,
+	}
+
+	for s, flags := range tests {
+		_, f, ok := parseTime(s)
+		if !ok || f != flags {
+			t.Errorf("wrong flags of %s. Expected: %b, received: %b", s, flags, f)
+		}
+	}
+
+	if _, _, ok := parseTime("yesterday"); ok {
+		t.Error("invalid time must not be parsed")
+	}
+}
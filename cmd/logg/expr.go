@@ -0,0 +1,389 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+// cond reports whether the entry matches a condition.
+type cond func(e *logg.Entry) bool
+
+// parseExpr compiles a filter expression. Comparisons of keys with values
+// are combined with &&, || and ! and grouped with parentheses:
+//
+//	level>=WRN && (file=="api.go" || req.method=~"^P") && latency>200ms
+//
+// Keys are level, message, file, line, time and field keys, dotted for
+// groups. Operators are ==, !=, <, <=, >, >=, =~ and !~ (regular expression).
+// Values are quoted strings or words: levels, numbers, durations and
+// timestamps. A file without a directory matches the file name only.
+// A comparison with a missing key is false.
+func parseExpr(s string) (cond, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+
+	return c, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators ordered to match longer operators first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")"}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			str, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: str, pos: i})
+			i = end + 1
+			continue
+		case isWordChar(c):
+			end := i
+			for end < len(s) && isWordChar(s[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[i:end], pos: i})
+			i = end
+			continue
+		}
+
+		op := ""
+		for _, o := range operators {
+			if strings.HasPrefix(s[i:], o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("unexpected %q at %d", c, i)
+		}
+		tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+		i += len(op)
+	}
+
+	return append(tokens, token{kind: tokenEnd, text: "end", pos: len(s)}), nil
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '.' || c == '-' || c == '+' || c == ':' || c == '/' || c == '@'
+}
+
+// exprParser is a recursive descent parser of expressions.
+type exprParser struct {
+	tokens []token
+	i      int
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.i] }
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEnd {
+		p.i++
+	}
+
+	return t
+}
+
+// accept skips the operator if it's the next token.
+func (p *exprParser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.i++
+		return true
+	}
+
+	return false
+}
+
+func (p *exprParser) or() (cond, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *logg.Entry) bool { return l(e) || right(e) }
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) and() (cond, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *logg.Entry) bool { return l(e) && right(e) }
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) unary() (cond, error) {
+	if p.accept("!") {
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *logg.Entry) bool { return !c(e) }, nil
+	}
+
+	if p.accept("(") {
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			t := p.peek()
+			return nil, fmt.Errorf("expected \")\" at %d, found %q", t.pos, t.text)
+		}
+		return c, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *exprParser) comparison() (cond, error) {
+	key := p.next()
+	if key.kind != tokenWord {
+		return nil, fmt.Errorf("expected a key at %d, found %q", key.pos, key.text)
+	}
+
+	op := p.next()
+	if !isComparison(op) {
+		return nil, fmt.Errorf("expected an operator after %s at %d, found %q", key.text, op.pos, op.text)
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("expected a value at %d, found %q", value.pos, value.text)
+	}
+
+	return newComparison(key.text, op.text, value.text)
+}
+
+func isComparison(t token) bool {
+	if t.kind != tokenOperator {
+		return false
+	}
+
+	switch t.text {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+		return true
+	}
+
+	return false
+}
+
+// newComparison returns a condition which compares the key of entries with the value.
+func newComparison(key, op, value string) (cond, error) {
+	if op == "=~" || op == "!~" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		match := op == "=~"
+		return func(e *logg.Entry) bool {
+			s, ok := entryText(e, key)
+			return ok && re.MatchString(s) == match
+		}, nil
+	}
+
+	switch key {
+	case "level":
+		level, err := logg.ParseLevel(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *logg.Entry) bool {
+			return e.Level != logg.Empty && compareInts(int64(e.Level), int64(level), op)
+		}, nil
+
+	case "time":
+		var t time.Time
+		if err := (timeFlag{t: &t, now: time.Now}).Set(value); err != nil {
+			return nil, err
+		}
+		return func(e *logg.Entry) bool {
+			return e.Flags&logg.Ldate != 0 && compareInts(e.Time.UnixNano(), t.UnixNano(), op)
+		}, nil
+
+	case "file":
+		base := !strings.Contains(value, "/")
+		return func(e *logg.Entry) bool {
+			file := e.File
+			if base {
+				file = path.Base(file)
+			}
+			return e.File != "" && compareStrings(file, value, op)
+		}, nil
+	}
+
+	v := newLiteral(value)
+	return func(e *logg.Entry) bool {
+		f, ok := entryField(e, key)
+		return ok && v.compare(f, op)
+	}, nil
+}
+
+// entryField returns the entry value of the key as a field.
+func entryField(e *logg.Entry, key string) (logg.Field, bool) {
+	switch key {
+	case "message":
+		return logg.String(key, string(e.Message)), true
+	case "line":
+		if e.File == "" {
+			return logg.Field{}, false
+		}
+		return logg.Int(key, e.Line), true
+	}
+
+	return findField(e.Fields, key)
+}
+
+// entryText returns the entry value of the key as a text.
+func entryText(e *logg.Entry, key string) (string, bool) {
+	switch key {
+	case "level":
+		return e.Level.String(), e.Level != logg.Empty
+	case "file":
+		return e.File, e.File != ""
+	case "time":
+		return e.Time.Format(time.RFC3339Nano), e.Flags&logg.Ldate != 0
+	}
+
+	f, ok := entryField(e, key)
+	if !ok {
+		return "", false
+	}
+
+	return string(f.AppendText(nil)), true
+}
+
+// literal is a value of a comparison, a value is compared
+// as a duration, a number or a string in this order.
+type literal struct {
+	text  string
+	dur   time.Duration
+	isDur bool
+	num   float64
+	isNum bool
+}
+
+func newLiteral(s string) literal {
+	v := literal{text: s}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		v.num, v.isNum = n, true
+	} else if d, err := time.ParseDuration(s); err == nil {
+		v.dur, v.isDur = d, true
+	}
+
+	return v
+}
+
+// compare compares the field value with the literal. The field is converted
+// to the literal type, a field which can't be converted doesn't match.
+func (v literal) compare(f logg.Field, op string) bool {
+	text := string(f.AppendText(nil))
+
+	switch {
+	case v.isDur:
+		d, err := time.ParseDuration(text)
+		return err == nil && compareInts(int64(d), int64(v.dur), op)
+	case v.isNum:
+		n, err := strconv.ParseFloat(text, 64)
+		return err == nil && compareFloats(n, v.num, op)
+	}
+
+	return compareStrings(text, v.text, op)
+}
+
+func compareInts(a, b int64, op string) bool { return compareResult(cmp.Compare(a, b), op) }
+
+func compareFloats(a, b float64, op string) bool { return compareResult(cmp.Compare(a, b), op) }
+
+func compareStrings(a, b, op string) bool { return compareResult(strings.Compare(a, b), op) }
+
+// compareResult applies the operator to the result of a comparison.
+func compareResult(c int, op string) bool {
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_parseExpr(t *testing.T) {
+	e, err := parseEntry([]byte(`{"time": "2020-01-02T03:04:05Z", "file": "service/api.go", "line": 10, "level": "WRN", "message": "slow request", "req": {"method": "POST"}, "latency": "250ms", "status": 201, "ratio": 0.5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		`level>=WRN`:                         true,
+		`level>=error`:                       false,
+		`level==warn`:                        true,
+		`file=="api.go"`:                     true,
+		`file=="service/api.go"`:             true,
+		`file=="other/api.go"`:               false,
+		`file=~"^service/"`:                  true,
+		`line==10 && line<=10`:               true,
+		`message=="slow request"`:            true,
+		`message=~slow && message!~fast`:     true,
+		`latency>200ms`:                      true,
+		`latency>1s`:                         false,
+		`status>=200 && status<300`:          true,
+		`ratio<1`:                            true,
+		`req.method==POST`:                   true,
+		`req.method==GET || req.method==PUT`: false,
+		`!(req.method==GET)`:                 true,
+		`missing==1`:                         false,
+		`missing!=1`:                         false,
+		`!(missing==1)`:                      true,
+		`time>=2020-01-02T03:04:05Z`:         true,
+		`time<2020-01-02T03:04:05Z`:          false,
+		`level>=WRN && (status==500 || latency>=250ms)`: true,
+		`level>=WRN && status==500 || latency>=250ms`:   true,
+		`level>=ERR && status==201 || latency>1s`:       false,
+	}
+
+	for expr, match := range tests {
+		c, err := parseExpr(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		if c(e) != match {
+			t.Errorf("wrong match of %s. Expected: %v, received: %v", expr, match, !match)
+		}
+	}
+}
+
+func Test_parseExpr_errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`level`,
+		`level>=`,
+		`level>=verbose`,
+		`level>=WRN &&`,
+		`(level>=WRN`,
+		`level>=WRN)`,
+		`message=~"("`,
+		`message=="unterminated`,
+		`time>yesterday`,
+		`status=200`,
+		`status==200 $`,
+		`"status"==200`,
+	} {
+		if _, err := parseExpr(expr); err == nil {
+			t.Errorf("%q must not be parsed", expr)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+// filter selects lines to print. Json lines are filtered by all
+// conditions, other lines only by grep.
+type filter struct {
+	level  logg.Level // minimum level, logg.Empty disables the condition
+	since  time.Time
+	until  time.Time
+	grep   *regexp.Regexp
+	fields fieldFilters
+}
+
+// match reports whether the line is printed. The entry is nil
+// if the line is not a json log line.
+func (f *filter) match(line []byte, e *logg.Entry) bool {
+	if f.grep != nil && !f.grep.Match(line) {
+		return false
+	}
+	if e == nil {
+		return true
+	}
+
+	if f.level != logg.Empty && e.Level != logg.Empty && e.Level < f.level {
+		return false
+	}
+
+	// entries without a date are not filtered by time
+	if e.Flags&logg.Ldate != 0 {
+		if !f.since.IsZero() && e.Time.Before(f.since) {
+			return false
+		}
+		if !f.until.IsZero() && !e.Time.Before(f.until) {
+			return false
+		}
+	}
+
+	for _, ff := range f.fields {
+		field, ok := findField(e.Fields, ff.key)
+		if !ok || string(field.AppendText(nil)) != ff.value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fieldFilter requires a field with the value.
+type fieldFilter struct {
+	key   string
+	value string
+}
+
+// fieldFilters is a flag.Value of key=value filters.
+type fieldFilters []fieldFilter
+
+func (f *fieldFilters) String() string {
+	s := make([]string, len(*f))
+	for i, ff := range *f {
+		s[i] = ff.key + "=" + ff.value
+	}
+
+	return strings.Join(s, ",")
+}
+
+func (f *fieldFilters) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return errors.New("field filter must be key=value")
+	}
+	*f = append(*f, fieldFilter{key: key, value: value})
+
+	return nil
+}
+
+// timeFlag is a flag.Value of a timestamp or a duration before now.
+type timeFlag struct {
+	t   *time.Time
+	now func() time.Time
+}
+
+// timeFlagLayouts are accepted timestamp layouts, time is local without a zone.
+var timeFlagLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func (f timeFlag) String() string {
+	if f.t == nil || f.t.IsZero() {
+		return ""
+	}
+
+	return f.t.Format(time.RFC3339)
+}
+
+func (f timeFlag) Set(s string) error {
+	if d, err := time.ParseDuration(s); err == nil {
+		*f.t = f.now().Add(-d)
+		return nil
+	}
+
+	for _, layout := range timeFlagLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			*f.t = t
+			return nil
+		}
+	}
+
+	return errors.New("time must be a timestamp (2006-01-02 15:04:05) or a duration (15m)")
+}
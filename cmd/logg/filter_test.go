@@ -0,0 +1,89 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+func Test_filter(t *testing.T) {
+	line := []byte(`{"time": "2020-01-02T03:04:05Z", "level": "WRN", "message": "slow request", "req": {"method": "GET"}}`)
+	e, err := parseEntry(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		filter filter
+		match  bool
+	}{
+		"empty":         {filter: filter{level: logg.Empty}, match: true},
+		"level":         {filter: filter{level: logg.Warning}, match: true},
+		"higher level":  {filter: filter{level: logg.Error}, match: false},
+		"since":         {filter: filter{level: logg.Empty, since: at}, match: true},
+		"since after":   {filter: filter{level: logg.Empty, since: at.Add(time.Second)}, match: false},
+		"until":         {filter: filter{level: logg.Empty, until: at.Add(time.Second)}, match: true},
+		"until equal":   {filter: filter{level: logg.Empty, until: at}, match: false},
+		"grep":          {filter: filter{level: logg.Empty, grep: regexp.MustCompile(`slow`)}, match: true},
+		"grep mismatch": {filter: filter{level: logg.Empty, grep: regexp.MustCompile(`fast`)}, match: false},
+		"field":         {filter: filter{level: logg.Empty, fields: fieldFilters{{key: "req.method", value: "GET"}}}, match: true},
+		"field value":   {filter: filter{level: logg.Empty, fields: fieldFilters{{key: "req.method", value: "POST"}}}, match: false},
+		"missing field": {filter: filter{level: logg.Empty, fields: fieldFilters{{key: "status", value: "200"}}}, match: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if match := tc.filter.match(line, e); match != tc.match {
+				t.Errorf("wrong match. Expected: %v, received: %v", tc.match, match)
+			}
+		})
+	}
+
+	plain := filter{level: logg.Error, fields: fieldFilters{{key: "status", value: "200"}}}
+	if !plain.match([]byte("plain text"), nil) {
+		t.Error("plain text lines must be filtered only by grep")
+	}
+}
+
+func Test_timeFlag(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+
+	tests := map[string]time.Time{
+		"15m":                  now.Add(-15 * time.Minute),
+		"2020-01-02T03:04:05Z": time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		"2020-01-02 03:04:05":  now,
+		"2020-01-02":           time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local),
+	}
+
+	for s, expected := range tests {
+		var v time.Time
+		if err := (timeFlag{t: &v, now: func() time.Time { return now }}).Set(s); err != nil || !v.Equal(expected) {
+			t.Errorf("wrong time of %s. Expected: %s, received: %s (%v)", s, expected, v, err)
+		}
+	}
+
+	var v time.Time
+	if err := (timeFlag{t: &v, now: time.Now}).Set("yesterday"); err == nil {
+		t.Error("invalid time must return an error")
+	}
+}
+
+func Test_fieldFilters(t *testing.T) {
+	var f fieldFilters
+	if err := f.Set("req.method=GET"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("query=a=b"); err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != "req.method=GET,query=a=b" {
+		t.Errorf("wrong filters: %s", f.String())
+	}
+
+	if err := f.Set("status"); err == nil {
+		t.Error("filter without a value must return an error")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// pollInterval is an interval of checks for new lines in followed files.
+var pollInterval = 250 * time.Millisecond
+
+// readLines sends lines of the reader without the trailing new line.
+// The line is valid until fn returns.
+func readLines(r io.Reader, fn func(line []byte)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		fn(sc.Bytes())
+	}
+
+	return sc.Err()
+}
+
+// readLogs reads lines of files or stdin if there are no files.
+func readLogs(files []string, stdin io.Reader, fn func(line []byte)) error {
+	if len(files) == 0 {
+		return readLog(stdin, fn)
+	}
+
+	for _, name := range files {
+		if err := readFile(name, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readLog reads lines of a plain or gzip compressed log.
+func readLog(r io.Reader, fn func(line []byte)) error {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gz.Close() }()
+
+		return readLines(gz, fn)
+	}
+
+	return readLines(br, fn)
+}
+
+// readFile reads lines of the file, the file can be gzip compressed.
+func readFile(name string, fn func(line []byte)) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	return readLog(f, fn)
+}
+
+// follow sends existing and appended lines of the file until the context
+// is done and the file is read to the end. The file is reopened if it is rotated or truncated. A line
+// without the trailing new line is sent when it is completed.
+func follow(ctx context.Context, name string, fn func(line []byte)) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReader(f)
+	var partial []byte
+	var offset int64
+	rotated, stopped := false, false
+
+	for {
+		line, err := r.ReadSlice('\n')
+		offset += int64(len(line))
+
+		switch {
+		case err == nil:
+			if len(partial) != 0 {
+				line = append(partial, line...)
+				partial = partial[:0]
+			}
+			fn(line[:len(line)-1])
+			continue
+		case errors.Is(err, bufio.ErrBufferFull):
+			partial = append(partial, line...)
+			continue
+		case !errors.Is(err, io.EOF):
+			return err
+		}
+		partial = append(partial, line...)
+
+		// the rotated file is read to the end, continue with the new file
+		if rotated {
+			if len(partial) != 0 {
+				fn(partial)
+				partial = partial[:0]
+			}
+
+			nf, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			_ = f.Close()
+			f, offset, rotated = nf, 0, false
+			r.Reset(f)
+			continue
+		}
+
+		if stopped {
+			if len(partial) != 0 {
+				fn(partial)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			stopped = true // read lines written before the stop
+			continue
+		case <-time.After(pollInterval):
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Size() < offset {
+			// truncated, read from the beginning
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			offset, partial = 0, partial[:0]
+			r.Reset(f)
+			continue
+		}
+
+		// a missing file is not created after the rotation yet
+		if current, err := os.Stat(name); err == nil && !os.SameFile(info, current) {
+			rotated = true
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_follow(t *testing.T) {
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = time.Millisecond
+
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(name, []byte("first\nsec"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var lines []string
+	received := func(n int) []string {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			mu.Lock()
+			l := append([]string(nil), lines...)
+			mu.Unlock()
+			if len(l) >= n || time.Now().After(deadline) {
+				return l
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- follow(ctx, name, func(line []byte) {
+			mu.Lock()
+			lines = append(lines, string(line))
+			mu.Unlock()
+		})
+	}()
+
+	appendFile := func(s string) {
+		f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+	}
+
+	appendFile("ond\n")
+	received(2)
+
+	// rotated, the rest of the old file is read before the new file
+	appendFile("third\n")
+	if err := os.Rename(name, name+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte("fourth\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	received(4)
+
+	// truncated
+	if err := os.WriteFile(name, []byte("fifth\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	received(5)
+
+	appendFile("last")
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"first", "second", "third", "fourth", "fifth", "last"}
+	if l := received(len(expected)); !reflect.DeepEqual(l, expected) {
+		t.Errorf("wrong lines. Expected: %q, received: %q", expected, l)
+	}
+}
@@ -0,0 +1,197 @@
+// Command logg renders json logs written by logg in the pretty format
+// and queries them.
+//
+//	logg [flags] [file ...]
+//	logg query [flags] [file ...]
+//
+// Lines are read from files or from stdin if there are no files, files
+// can be gzip compressed. Lines which are not json objects are printed
+// unchanged by logg and skipped by logg query.
+//
+//	logg -level WRN -since 1h -field req.method=GET app.log
+//	kubectl logs -f api | logg -grep timeout
+//	logg query -where 'level>=WRN && latency>200ms' -by file app.log app-*.log.gz
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var err error
+	if len(args) != 0 && args[0] == "query" {
+		err = query(args[1:], stdin, stdout, stderr)
+	} else {
+		err = pretty(ctx, args, stdin, stdout, stderr)
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	}
+
+	_, _ = fmt.Fprintf(stderr, "logg: %v\n", err)
+	return 1
+}
+
+// errUsage is returned for invalid flags, the flag set prints the error.
+var errUsage = errors.New("invalid usage")
+
+// printer writes lines which match the filter in the pretty format.
+type printer struct {
+	filter filter
+	color  bool
+
+	mu  sync.Mutex
+	w   *bufio.Writer
+	buf []byte
+}
+
+// print writes the line. Json lines are formatted, other lines are written as is.
+func (p *printer) print(line []byte) {
+	e, _ := parseEntry(line)
+	if !p.filter.match(line, e) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e == nil {
+		_, _ = p.w.Write(line)
+		_ = p.w.WriteByte('\n')
+		return
+	}
+
+	e.Color = p.color
+	p.buf = logg.Pretty.Formatter().Format(p.buf[:0], e)
+	p.buf = append(p.buf, '\n')
+	_, _ = p.w.Write(p.buf)
+}
+
+// flush writes buffered lines.
+func (p *printer) flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.w.Flush()
+}
+
+func pretty(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	p := &printer{
+		filter: filter{level: logg.Empty},
+		w:      bufio.NewWriter(stdout),
+	}
+
+	fs := flag.NewFlagSet("logg", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: logg [flags] [file ...]\n       logg query [flags] [file ...]")
+		fs.PrintDefaults()
+	}
+
+	level := fs.String("level", "", "minimum level (TRC, DBG, INF, WRN, ERR, PNC, FTL)")
+	grep := fs.String("grep", "", "print lines which match the regular expression")
+	fs.Var(timeFlag{t: &p.filter.since, now: time.Now}, "since", "print entries since the time (2006-01-02 15:04:05) or the duration before now (15m)")
+	fs.Var(timeFlag{t: &p.filter.until, now: time.Now}, "until", "print entries before the time or the duration before now")
+	fs.Var(&p.filter.fields, "field", "print entries with the field value (key=value, req.method=GET), can be repeated")
+	followFiles := fs.Bool("follow", false, "print lines appended to files")
+	fs.BoolVar(followFiles, "f", false, "shorthand for -follow")
+	fs.BoolVar(&p.color, "color", isTerminal(stdout), "colorize output")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+
+	if *level != "" {
+		l, err := logg.ParseLevel(*level)
+		if err != nil {
+			return err
+		}
+		p.filter.level = l
+	}
+	if *grep != "" {
+		re, err := regexp.Compile(*grep)
+		if err != nil {
+			return err
+		}
+		p.filter.grep = re
+	}
+
+	files := fs.Args()
+	if *followFiles && len(files) == 0 {
+		return errors.New("-follow requires files")
+	}
+
+	err := printFiles(ctx, p, files, stdin, *followFiles)
+	if ferr := p.flush(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
+
+// printFiles prints lines of files one by one or of all files
+// concurrently if they are followed. Files which aren't followed
+// can be gzip compressed.
+func printFiles(ctx context.Context, p *printer, files []string, stdin io.Reader, followFiles bool) error {
+	if !followFiles {
+		return readLogs(files, stdin, p.print)
+	}
+
+	printLine := func(line []byte) {
+		p.print(line)
+		_ = p.flush()
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(files))
+	for i, name := range files {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			errs[i] = follow(ctx, name, printLine)
+		}(i, name)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// isTerminal reports whether the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLog = `{"time": "2020-01-02T03:04:05.000006Z", "file": "api.go", "line": 10, "level": "INF", "message": "request", "req": {"method": "GET"}, "status": 200}
+plain text
+{"time": "2020-01-02T03:04:06Z", "level": "ERR", "message": "failed", "error": "timeout"}
+{"level": "DBG", "message": "debug"}
+`
+
+func Test_run(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"all": {
+			expected: "2020-01-02 03:04:05.000006 api.go:10 INF request req.method=GET status=200\n" +
+				"plain text\n" +
+				"2020-01-02 03:04:06 ERR failed error=timeout\n" +
+				"DBG debug\n",
+		},
+		"level": {
+			args:     []string{"-level", "error"},
+			expected: "plain text\n2020-01-02 03:04:06 ERR failed error=timeout\n",
+		},
+		"field": {
+			args:     []string{"-field", "req.method=GET", "-grep", "GET"},
+			expected: "2020-01-02 03:04:05.000006 api.go:10 INF request req.method=GET status=200\n",
+		},
+		"until": {
+			args:     []string{"-until", "2020-01-02T03:04:06Z", "-level", "INF"},
+			expected: "2020-01-02 03:04:05.000006 api.go:10 INF request req.method=GET status=200\nplain text\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			code := run(context.Background(), tc.args, strings.NewReader(testLog), stdout, stderr)
+
+			if code != 0 {
+				t.Fatalf("wrong exit code: %d, %s", code, stderr.String())
+			}
+			if stdout.String() != tc.expected {
+				t.Errorf("wrong output.\nExpected: %q\nReceived: %q", tc.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func Test_run_files(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(name, []byte(testLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := run(context.Background(), []string{"-color", "-level", "ERR", name, name}, nil, stdout, stderr); code != 0 {
+		t.Fatalf("wrong exit code: %d, %s", code, stderr.String())
+	}
+	if n := strings.Count(stdout.String(), "\x1b[31m\x1b[1mERR"); n != 2 {
+		t.Errorf("each file must be printed with colors: %q", stdout.String())
+	}
+}
+
+func Test_run_errors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		code int
+	}{
+		"unknown flag":  {args: []string{"-unknown"}, code: 2},
+		"invalid level": {args: []string{"-level", "verbose"}, code: 1},
+		"invalid grep":  {args: []string{"-grep", "("}, code: 1},
+		"missing file":  {args: []string{filepath.Join(t.TempDir(), "missing.log")}, code: 1},
+		"follow stdin":  {args: []string{"-follow"}, code: 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			if code := run(context.Background(), tc.args, strings.NewReader(""), &bytes.Buffer{}, stderr); code != tc.code {
+				t.Errorf("wrong exit code. Expected: %d, received: %d", tc.code, code)
+			}
+			if stderr.Len() == 0 {
+				t.Error("error must be written")
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+const (
+	// maxBuckets limits the number of histogram rows.
+	maxBuckets = 10000
+	// barWidth is the width of the longest histogram bar.
+	barWidth = 50
+)
+
+// query filters and aggregates json lines of files. Lines which are not
+// json objects are skipped. Matched lines are printed unchanged if there
+// is no aggregation.
+//
+//	logg query -where 'level>=WRN && latency>200ms' -by file,level app.log app-*.log.gz
+//	logg query -where 'level>=ERR' -top 10 app.log
+//	logg query -histogram 5m app.log
+func query(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("logg query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: logg query [flags] [file ...]")
+		fs.PrintDefaults()
+	}
+
+	where := fs.String("where", "", `filter expression, e.g. level>=WRN && file=="api.go" && latency>200ms`)
+	by := fs.String("by", "", "count entries by comma separated keys, e.g. level,req.method")
+	top := fs.Int("top", 0, "print the most frequent groups only, groups entries by message without -by")
+	histogram := fs.Duration("histogram", 0, "count entries per time bucket, e.g. 1m")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+
+	match := func(*logg.Entry) bool { return true }
+	if *where != "" {
+		c, err := parseExpr(*where)
+		if err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+		match = c
+	}
+
+	if *histogram < 0 {
+		return errors.New("histogram interval must be positive")
+	}
+	if *histogram != 0 && (*by != "" || *top != 0) {
+		return errors.New("histogram can't be used with -by and -top")
+	}
+
+	var keys []string
+	if *by != "" {
+		keys = strings.Split(*by, ",")
+	} else if *top > 0 {
+		keys = []string{"message"}
+	}
+
+	w := bufio.NewWriter(stdout)
+	var agg aggregator
+	switch {
+	case *histogram != 0:
+		agg = &histogramAggregator{interval: *histogram, buckets: map[int64]int{}}
+	case len(keys) != 0:
+		agg = &groupAggregator{keys: keys, top: *top, index: map[string]int{}}
+	default:
+		agg = &lineWriter{w: w}
+	}
+
+	err := readLogs(fs.Args(), stdin, func(line []byte) {
+		e, err := parseEntry(line)
+		if err == nil && match(e) {
+			agg.add(line, e)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := agg.write(w); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// aggregator collects matched entries and writes the result.
+type aggregator interface {
+	add(line []byte, e *logg.Entry)
+	write(w io.Writer) error
+}
+
+// lineWriter writes matched lines.
+type lineWriter struct {
+	w *bufio.Writer
+}
+
+func (a *lineWriter) add(line []byte, _ *logg.Entry) {
+	_, _ = a.w.Write(line)
+	_ = a.w.WriteByte('\n')
+}
+
+func (a *lineWriter) write(io.Writer) error { return nil }
+
+// groupAggregator counts entries by values of keys.
+type groupAggregator struct {
+	keys []string
+	top  int
+
+	groups []group
+	index  map[string]int // group index by joined values
+}
+
+type group struct {
+	values []string
+	count  int
+}
+
+func (a *groupAggregator) add(_ []byte, e *logg.Entry) {
+	values := make([]string, len(a.keys))
+	for i, key := range a.keys {
+		v, ok := entryText(e, key)
+		if !ok {
+			v = "-"
+		}
+		values[i] = v
+	}
+
+	id := strings.Join(values, "\x00")
+	if i, ok := a.index[id]; ok {
+		a.groups[i].count++
+		return
+	}
+	a.index[id] = len(a.groups)
+	a.groups = append(a.groups, group{values: values, count: 1})
+}
+
+// write writes groups from the most frequent, groups with the same count
+// are ordered by values.
+func (a *groupAggregator) write(w io.Writer) error {
+	sort.SliceStable(a.groups, func(i, j int) bool {
+		if a.groups[i].count != a.groups[j].count {
+			return a.groups[i].count > a.groups[j].count
+		}
+		return strings.Join(a.groups[i].values, "\x00") < strings.Join(a.groups[j].values, "\x00")
+	})
+
+	groups := a.groups
+	if a.top > 0 && len(groups) > a.top {
+		groups = groups[:a.top]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "COUNT\t%s\n", strings.ToUpper(strings.Join(a.keys, "\t")))
+	for _, g := range groups {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\n", g.count, strings.Join(g.values, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// histogramAggregator counts entries per time bucket.
+// Entries without a date are skipped.
+type histogramAggregator struct {
+	interval time.Duration
+	loc      *time.Location // location of the first entry
+
+	buckets  map[int64]int // counts by bucket start in unix nanoseconds
+	min, max int64
+}
+
+func (a *histogramAggregator) add(_ []byte, e *logg.Entry) {
+	if e.Flags&logg.Ldate == 0 {
+		return
+	}
+
+	t := e.Time.Truncate(a.interval).UnixNano()
+	if a.loc == nil {
+		a.loc, a.min, a.max = e.Time.Location(), t, t
+	}
+	if t < a.min {
+		a.min = t
+	}
+	if t > a.max {
+		a.max = t
+	}
+	a.buckets[t]++
+}
+
+// write writes all buckets between the first and the last entry.
+func (a *histogramAggregator) write(w io.Writer) error {
+	if a.loc == nil {
+		return nil
+	}
+
+	step := int64(a.interval)
+	if (a.max-a.min)/step >= maxBuckets {
+		return fmt.Errorf("more than %d buckets, use a larger interval", maxBuckets)
+	}
+
+	top := 0
+	for _, n := range a.buckets {
+		if n > top {
+			top = n
+		}
+	}
+
+	width := len(strconv.Itoa(top))
+	if width < len("COUNT") {
+		width = len("COUNT")
+	}
+
+	_, _ = fmt.Fprintf(w, "%-19s  %*s\n", "TIME", width, "COUNT")
+	for t := a.min; t <= a.max; t += step {
+		n := a.buckets[t]
+		row := fmt.Sprintf("%s  %*d  %s", time.Unix(0, t).In(a.loc).Format("2006-01-02 15:04:05"), width, n,
+			strings.Repeat("#", (n*barWidth+top-1)/top))
+		if _, err := fmt.Fprintln(w, strings.TrimRight(row, " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const queryLog = `{"time": "2020-01-02T03:04:05Z", "file": "service/api.go", "line": 10, "level": "INF", "message": "request", "req": {"method": "GET"}, "latency": "150ms"}
+{"time": "2020-01-02T03:05:05Z", "file": "service/api.go", "line": 10, "level": "WRN", "message": "slow request", "req": {"method": "POST"}, "latency": "1.2s"}
+not json
+{"time": "2020-01-02T03:07:30Z", "file": "db.go", "line": 5, "level": "ERR", "message": "query failed", "latency": "250ms"}
+{"time": "2020-01-02T03:07:40Z", "file": "service/api.go", "line": 12, "level": "WRN", "message": "slow request", "req": {"method": "GET"}, "latency": "300ms"}
+`
+
+func Test_query(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"where": {
+			args: []string{"-where", `level>=WRN && file=="api.go" && latency>200ms`},
+			expected: `{"time": "2020-01-02T03:05:05Z", "file": "service/api.go", "line": 10, "level": "WRN", "message": "slow request", "req": {"method": "POST"}, "latency": "1.2s"}` + "\n" +
+				`{"time": "2020-01-02T03:07:40Z", "file": "service/api.go", "line": 12, "level": "WRN", "message": "slow request", "req": {"method": "GET"}, "latency": "300ms"}` + "\n",
+		},
+		"by": {
+			args: []string{"-by", "level,req.method"},
+			expected: "COUNT  LEVEL  REQ.METHOD\n" +
+				"1      ERR    -\n" +
+				"1      INF    GET\n" +
+				"1      WRN    GET\n" +
+				"1      WRN    POST\n",
+		},
+		"by with top": {
+			args:     []string{"-by", "file", "-top", "1", "-where", "level>=INF"},
+			expected: "COUNT  FILE\n3      service/api.go\n",
+		},
+		"top messages": {
+			args:     []string{"-top", "2"},
+			expected: "COUNT  MESSAGE\n2      slow request\n1      query failed\n",
+		},
+		"histogram": {
+			args: []string{"-histogram", "1m"},
+			expected: "TIME                 COUNT\n" +
+				"2020-01-02 03:04:00      1  " + strings.Repeat("#", 25) + "\n" +
+				"2020-01-02 03:05:00      1  " + strings.Repeat("#", 25) + "\n" +
+				"2020-01-02 03:06:00      0\n" +
+				"2020-01-02 03:07:00      2  " + strings.Repeat("#", 50) + "\n",
+		},
+		"no matches": {
+			args:     []string{"-histogram", "1m", "-where", "level>=FTL"},
+			expected: "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			code := run(context.Background(), append([]string{"query"}, tc.args...), strings.NewReader(queryLog), stdout, stderr)
+
+			if code != 0 {
+				t.Fatalf("wrong exit code: %d, %s", code, stderr.String())
+			}
+			if stdout.String() != tc.expected {
+				t.Errorf("wrong output.\nExpected: %q\nReceived: %q", tc.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func Test_query_files(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(plain, []byte(queryLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	if _, err := gz.Write([]byte(queryLog)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	compressed := filepath.Join(dir, "app-2020-01-02T03-04-05.000.log.gz")
+	if err := os.WriteFile(compressed, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := run(context.Background(), []string{"query", "-by", "level", plain, compressed}, nil, stdout, stderr); code != 0 {
+		t.Fatalf("wrong exit code: %d, %s", code, stderr.String())
+	}
+
+	expected := "COUNT  LEVEL\n4      WRN\n2      ERR\n2      INF\n"
+	if stdout.String() != expected {
+		t.Errorf("wrong output.\nExpected: %q\nReceived: %q", expected, stdout.String())
+	}
+}
+
+func Test_query_errors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		code int
+	}{
+		"unknown flag":       {args: []string{"-unknown"}, code: 2},
+		"invalid expression": {args: []string{"-where", "level>>WRN"}, code: 1},
+		"histogram with by":  {args: []string{"-histogram", "1m", "-by", "level"}, code: 1},
+		"negative histogram": {args: []string{"-histogram", "-1m"}, code: 1},
+		"too many buckets":   {args: []string{"-histogram", "1ms"}, code: 1},
+		"missing file":       {args: []string{filepath.Join(t.TempDir(), "missing.log")}, code: 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			code := run(context.Background(), append([]string{"query"}, tc.args...), strings.NewReader(queryLog), &bytes.Buffer{}, stderr)
+			if code != tc.code {
+				t.Errorf("wrong exit code. Expected: %d, received: %d", tc.code, code)
+			}
+			if stderr.Len() == 0 {
+				t.Error("error must be written")
+			}
+		})
+	}
+}
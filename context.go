@@ -0,0 +1,127 @@
+package logg
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ContextFunc appends fields taken from the context to dst.
+type ContextFunc func(ctx context.Context, dst []Field) []Field
+
+type loggerKey struct{}
+
+var (
+	contextMu    sync.Mutex
+	contextFuncs atomic.Value // []ContextFunc
+)
+
+// NewContext returns a copy of ctx which carries the logger.
+func NewContext(ctx context.Context, l *Logg) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger from the context or the global logger.
+func FromContext(ctx context.Context) *Logg {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logg); ok && l != nil {
+			return l
+		}
+	}
+
+	return logg.Load()
+}
+
+// RegisterContextKey adds a field with the name to each message written
+// with a context which has a value for the key.
+//
+//	logg.RegisterContextKey(requestIDKey{}, "request_id")
+//	log.InfoCtx(ctx, "request") // INF request request_id=...
+func RegisterContextKey(key interface{}, name string) {
+	RegisterContextFunc(func(ctx context.Context, dst []Field) []Field {
+		if v := ctx.Value(key); v != nil {
+			return append(dst, Any(name, v))
+		}
+		return dst
+	})
+}
+
+// RegisterContextFunc adds a function which takes fields from the context
+// of each message written with a context (e.g. a trace ID of a span).
+func RegisterContextFunc(fn ContextFunc) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+
+	list, _ := contextFuncs.Load().([]ContextFunc)
+	updated := make([]ContextFunc, len(list), len(list)+1)
+	copy(updated, list)
+	contextFuncs.Store(append(updated, fn))
+}
+
+// appendContextFields appends fields of registered context keys and functions.
+func appendContextFields(dst []Field, ctx context.Context) []Field {
+	list, _ := contextFuncs.Load().([]ContextFunc)
+	for _, fn := range list {
+		dst = fn(ctx, dst)
+	}
+
+	return dst
+}
+
+func (l *Logg) PrintCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Empty, []byte(msg), keysAndValues)
+}
+
+func (l *Logg) TraceCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Trace, []byte(msg), keysAndValues)
+}
+
+func (l *Logg) DebugCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Debug, []byte(msg), keysAndValues)
+}
+
+func (l *Logg) InfoCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Info, []byte(msg), keysAndValues)
+}
+
+func (l *Logg) WarnCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Warning, []byte(msg), keysAndValues)
+}
+
+func (l *Logg) ErrorCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Error, []byte(msg), keysAndValues)
+}
+
+// PanicCtx writes a message with fields and panics with the message.
+func (l *Logg) PanicCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Panic, []byte(msg), keysAndValues)
+	l.panicMessage(msg)
+}
+
+// FatalCtx writes a message with fields, flushes the writer and exits with status 1.
+func (l *Logg) FatalCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.writeContext(ctx, 1, Fatal, []byte(msg), keysAndValues)
+	l.fatal()
+}
+
+// Global, messages are written by the logger from the context
+
+func TraceCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	FromContext(ctx).writeContext(ctx, 1, Trace, []byte(msg), keysAndValues)
+}
+
+func DebugCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	FromContext(ctx).writeContext(ctx, 1, Debug, []byte(msg), keysAndValues)
+}
+
+func InfoCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	FromContext(ctx).writeContext(ctx, 1, Info, []byte(msg), keysAndValues)
+}
+
+func WarnCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	FromContext(ctx).writeContext(ctx, 1, Warning, []byte(msg), keysAndValues)
+}
+
+func ErrorCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	FromContext(ctx).writeContext(ctx, 1, Error, []byte(msg), keysAndValues)
+}
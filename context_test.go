@@ -0,0 +1,106 @@
+package logg
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+type tenantKey struct{}
+
+func init() {
+	RegisterContextKey(requestIDKey{}, "request_id")
+	RegisterContextFunc(func(ctx context.Context, dst []Field) []Field {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return append(dst, Group("tenant", String("name", tenant)))
+		}
+		return dst
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	logger := New(nil)
+
+	if l := FromContext(context.Background()); l != logg.Load() {
+		t.Error("global logger must be returned without a logger in the context")
+	}
+	if l := FromContext(NewContext(context.Background(), logger)); l != logger {
+		t.Error("logger from the context must be returned")
+	}
+}
+
+func TestLogg_InfoCtx(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf).With(String("service", "api"))
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	logger.InfoCtx(ctx, "test", "id", 1)
+	if output := readFromBuffer(buf); output != "INF test service=api request_id=abc tenant.name=acme id=1" {
+		t.Errorf("wrong output: %s", output)
+	}
+
+	logger.WarnCtx(context.Background(), "test")
+	if output := readFromBuffer(buf); output != "WRN test service=api" {
+		t.Errorf("wrong output without context values: %s", output)
+	}
+
+	logger.SetFormat(Json)
+	logger.ErrorCtx(ctx, "test")
+	expected := `{"level": "ERR", "message": "test", "service": "api", "request_id": "abc", "tenant": {"name": "acme"}}`
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong json output: %s", output)
+	}
+
+	logger.DebugCtx(ctx, "test")
+	if output := readFromBuffer(buf); output != "" {
+		t.Errorf("debug message must not be written: %s", output)
+	}
+}
+
+func TestInfoCtx(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.SetFlags(Lshortfile)
+	logger.ToggleColor(false)
+
+	ctx := NewContext(context.WithValue(context.Background(), requestIDKey{}, 7), logger)
+
+	InfoCtx(ctx, "test")
+	_, _, line, _ := runtime.Caller(0)
+
+	expected := "context_test.go:" + strconv.Itoa(line-1) + " INF test request_id=7"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong output. Expected: %s, received: %s", expected, output)
+	}
+
+	logger.InfoCtx(ctx, "test")
+	_, _, line, _ = runtime.Caller(0)
+
+	expected = "context_test.go:" + strconv.Itoa(line-1) + " INF test request_id=7"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong output. Expected: %s, received: %s", expected, output)
+	}
+}
+
+func TestSlogHandler_context(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+	slog.New(NewSlogHandler(logger)).InfoContext(ctx, "test", "id", 1)
+
+	if output := readFromBuffer(buf); output != "INF test request_id=abc id=1" {
+		t.Errorf("wrong output: %s", output)
+	}
+}
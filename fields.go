@@ -0,0 +1,355 @@
+package logg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+type fieldType uint8
+
+const (
+	unknownType fieldType = iota
+	stringType
+	intType
+	uintType
+	floatType
+	boolType
+	durationType
+	timeType
+	errorType
+	anyType
+	groupType
+)
+
+// badKey is used as a key for values which were passed without a key.
+const badKey = "!BADKEY"
+
+// A Field is a key/value pair attached to a log entry.
+// Use constructors (String, Int, Duration, ...) to create a field.
+type Field struct {
+	Key string
+
+	typ fieldType
+	num uint64
+	str string
+	val interface{}
+}
+
+func String(key string, value string) Field {
+	return Field{Key: key, typ: stringType, str: value}
+}
+
+func Int(key string, value int) Field {
+	return Field{Key: key, typ: intType, num: uint64(value)}
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, typ: intType, num: uint64(value)}
+}
+
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, typ: uintType, num: value}
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, typ: floatType, num: math.Float64bits(value)}
+}
+
+func Bool(key string, value bool) Field {
+	var num uint64
+	if value {
+		num = 1
+	}
+	return Field{Key: key, typ: boolType, num: num}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, typ: durationType, num: uint64(value)}
+}
+
+func Time(key string, value time.Time) Field {
+	if ns := value.UnixNano(); time.Unix(0, ns).Equal(value) {
+		return Field{Key: key, typ: timeType, num: uint64(ns), val: value.Location()}
+	}
+
+	// nanoseconds overflow before 1678 and after 2262, the time is kept as is
+	return Field{Key: key, typ: timeType, val: value}
+}
+
+// Err creates a field with the "error" key.
+func Err(err error) Field {
+	return Field{Key: "error", typ: errorType, val: err}
+}
+
+// Group creates a field which contains other fields. It is written as
+// a nested object in json and as prefixed keys (key.field=value) in text formats.
+// Fields of a group with an empty key are written inline.
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, typ: groupType, val: fields}
+}
+
+// Any creates a field from any value. Known types are converted to a typed field.
+func Any(key string, value interface{}) Field {
+	switch v := value.(type) {
+	case Field:
+		return v
+	case string:
+		return String(key, v)
+	case []byte:
+		return String(key, string(v))
+	case int:
+		return Int(key, v)
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint:
+		return Uint64(key, uint64(v))
+	case uint8:
+		return Uint64(key, uint64(v))
+	case uint16:
+		return Uint64(key, uint64(v))
+	case uint32:
+		return Uint64(key, uint64(v))
+	case uint64:
+		return Uint64(key, v)
+	case float32:
+		return Float64(key, float64(v))
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case error:
+		return Field{Key: key, typ: errorType, val: v}
+	default:
+		return Field{Key: key, typ: anyType, val: v}
+	}
+}
+
+// appendKeyValues converts alternating keys and values to fields.
+// Field values are taken as is.
+func appendKeyValues(dst []Field, kv []interface{}) []Field {
+	for i := 0; i < len(kv); i++ {
+		if f, ok := kv[i].(Field); ok {
+			dst = append(dst, f)
+			continue
+		}
+
+		key, ok := kv[i].(string)
+		if !ok || i == len(kv)-1 {
+			dst = append(dst, Any(badKey, kv[i]))
+			continue
+		}
+
+		i++
+		dst = append(dst, Any(key, kv[i]))
+	}
+
+	return dst
+}
+
+// Value returns the field value.
+func (f Field) Value() interface{} {
+	switch f.typ {
+	case stringType:
+		return f.str
+	case intType:
+		return int64(f.num)
+	case uintType:
+		return f.num
+	case floatType:
+		return math.Float64frombits(f.num)
+	case boolType:
+		return f.num == 1
+	case durationType:
+		return time.Duration(f.num)
+	case timeType:
+		if t, ok := f.val.(time.Time); ok {
+			return t
+		}
+		t := time.Unix(0, int64(f.num))
+		if loc, ok := f.val.(*time.Location); ok && loc != nil {
+			t = t.In(loc)
+		}
+		return t
+	}
+
+	return f.val
+}
+
+// AppendText appends a text representation of the field value to dst.
+func (f Field) AppendText(dst []byte) []byte {
+	return f.appendValue(dst)
+}
+
+// AppendJSON appends the field value encoded as a json value to dst.
+func (f Field) AppendJSON(dst []byte) []byte {
+	return f.appendJSON(dst)
+}
+
+// stringValue returns the value of a string-like field.
+func (f Field) stringValue() (string, bool) {
+	switch f.typ {
+	case stringType:
+		return f.str, true
+	case errorType:
+		if f.val == nil {
+			return "<nil>", true
+		}
+		return f.val.(error).Error(), true
+	case anyType:
+		return fmt.Sprint(f.val), true
+	}
+
+	return "", false
+}
+
+// appendValue appends a text representation of the field value to dst.
+func (f Field) appendValue(dst []byte) []byte {
+	switch f.typ {
+	case intType:
+		return strconv.AppendInt(dst, int64(f.num), 10)
+	case uintType:
+		return strconv.AppendUint(dst, f.num, 10)
+	case floatType:
+		return strconv.AppendFloat(dst, math.Float64frombits(f.num), 'g', -1, 64)
+	case boolType:
+		return strconv.AppendBool(dst, f.num == 1)
+	case durationType:
+		return append(dst, time.Duration(f.num).String()...)
+	case timeType:
+		return f.Value().(time.Time).AppendFormat(dst, time.RFC3339Nano)
+	case groupType:
+		dst = append(dst, '{')
+		dst = appendPrettyFields(dst, f.val.([]Field))
+		return append(dst, '}')
+	}
+
+	s, _ := f.stringValue()
+	return append(dst, s...)
+}
+
+// needsQuote reports whether the value must be quoted in key=value output.
+func needsQuote(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '"' || c == '=' || c == '\\' || c == 0x7f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendTextValue appends s to dst, quotes it if needed.
+func appendTextValue(dst []byte, s string) []byte {
+	if needsQuote(s) {
+		return strconv.AppendQuote(dst, s)
+	}
+
+	return append(dst, s...)
+}
+
+// appendPrettyFields appends fields as key=value pairs separated by space.
+func appendPrettyFields(dst []byte, fields []Field) []byte {
+	return appendPrefixedFields(dst, "", fields)
+}
+
+// appendTextKey appends the key of a key=value pair. Spaces, quotes,
+// equal signs and control characters are replaced with underscores,
+// so the pair can be parsed.
+func appendTextKey(dst []byte, key string) []byte {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			dst = append(dst, '_')
+		} else {
+			dst = append(dst, c)
+		}
+	}
+
+	return dst
+}
+
+// appendPrefixedFields appends fields as prefix.key=value pairs.
+func appendPrefixedFields(dst []byte, prefix string, fields []Field) []byte {
+	for _, f := range fields {
+		if f.typ == groupType {
+			p := prefix
+			switch {
+			case f.Key == "":
+			case prefix == "":
+				p = f.Key
+			default:
+				p = prefix + "." + f.Key
+			}
+			dst = appendPrefixedFields(dst, p, f.val.([]Field))
+			continue
+		}
+
+		if len(dst) != 0 && dst[len(dst)-1] != ' ' && dst[len(dst)-1] != '{' {
+			dst = append(dst, ' ')
+		}
+
+		if prefix != "" {
+			dst = appendTextKey(dst, prefix)
+			dst = append(dst, '.')
+		}
+		dst = appendTextKey(dst, f.Key)
+		dst = append(dst, '=')
+
+		if s, ok := f.stringValue(); ok {
+			dst = appendTextValue(dst, s)
+		} else {
+			dst = f.appendValue(dst)
+		}
+	}
+
+	return dst
+}
+
+// bound contains fields attached to the logger and
+// their representation in each output format.
+// Pretty and Logfmt formats share the same key=value representation.
+type bound struct {
+	fields []Field
+
+	pretty []byte
+	json   []byte
+}
+
+func (b *bound) len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.fields)
+}
+
+// with returns a new bound which contains fields from b and provided fields.
+func (b *bound) with(fields []Field) *bound {
+	nb := &bound{}
+	if b != nil {
+		nb.fields = append(nb.fields, b.fields...)
+	}
+	nb.fields = append(nb.fields, fields...)
+
+	nb.pretty = appendPrettyFields(nil, nb.fields)
+
+	js := &json{}
+	js.appendFields(nb.fields)
+	nb.json = js.buf
+
+	return nb
+}
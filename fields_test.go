@@ -0,0 +1,109 @@
+package logg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_appendKeyValues(t *testing.T) {
+	tests := map[string]struct {
+		kv     []interface{}
+		fields []Field
+	}{
+		"empty": {},
+		"pairs": {
+			kv:     []interface{}{"user", "bob", "id", 10},
+			fields: []Field{String("user", "bob"), Int("id", 10)},
+		},
+		"fields": {
+			kv:     []interface{}{Bool("ok", true), "id", uint8(1)},
+			fields: []Field{Bool("ok", true), Uint64("id", 1)},
+		},
+		"missing value": {
+			kv:     []interface{}{"user"},
+			fields: []Field{String(badKey, "user")},
+		},
+		"wrong key": {
+			kv:     []interface{}{1, "user", "bob"},
+			fields: []Field{Int(badKey, 1), String("user", "bob")},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fields := appendKeyValues(nil, tc.kv)
+
+			if len(fields) != len(tc.fields) {
+				t.Fatalf("wrong number of fields. Expected: %d, received: %d", len(tc.fields), len(fields))
+			}
+
+			for i := range fields {
+				if fields[i] != tc.fields[i] {
+					t.Errorf("wrong field %d. Expected: %v, received: %v", i, tc.fields[i], fields[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_appendPrettyFields(t *testing.T) {
+	tests := map[string]struct {
+		field  Field
+		result string
+	}{
+		"string":         {field: String("k", "v"), result: "k=v"},
+		"string spaces":  {field: String("k", "a b"), result: `k="a b"`},
+		"string empty":   {field: String("k", ""), result: `k=""`},
+		"string quotes":  {field: String("k", `a"b`), result: `k="a\"b"`},
+		"int":            {field: Int("k", -10), result: "k=-10"},
+		"uint":           {field: Uint64("k", 10), result: "k=10"},
+		"float":          {field: Float64("k", 1.5), result: "k=1.5"},
+		"bool":           {field: Bool("k", true), result: "k=true"},
+		"duration":       {field: Duration("k", time.Second), result: "k=1s"},
+		"time":           {field: Time("k", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), result: "k=2020-01-02T03:04:05Z"},
+		"zero time":      {field: Time("k", time.Time{}), result: "k=0001-01-01T00:00:00Z"},
+		"distant time":   {field: Time("k", time.Date(3000, 1, 2, 3, 4, 5, 6, time.UTC)), result: "k=3000-01-02T03:04:05.000000006Z"},
+		"error":          {field: Err(errors.New("failed")), result: "error=failed"},
+		"nil error":      {field: Err(nil), result: "error=<nil>"},
+		"any":            {field: Any("k", []int{1, 2}), result: `k="[1 2]"`},
+		"any with known": {field: Any("k", int32(5)), result: "k=5"},
+		"key with space": {field: Int("a b", 1), result: "a_b=1"},
+		"key with equal": {field: Int("a=b", 1), result: "a_b=1"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := appendPrettyFields(nil, []Field{tc.field})
+			if string(buf) != tc.result {
+				t.Errorf("wrong field output. Expected: %s, received: %s", tc.result, string(buf))
+			}
+		})
+	}
+}
+
+func TestLogg_Infow(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.color = false
+
+	logger.Infow("test", "user", "bob", "latency", time.Millisecond)
+	expected := "INF test user=bob latency=1ms"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong pretty output. Expected: %s, received: %s", expected, output)
+	}
+
+	logger.format = Json
+	logger.Infow("test", "user", "bob", Int("id", 1))
+	expected = `{"level": "INF", "message": "test", "user": "bob", "id": 1}`
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong json output. Expected: %s, received: %s", expected, output)
+	}
+
+	logger.Debugw("test", "user", "bob")
+	if buf.Len() != 0 {
+		t.Errorf("debug message must be missed. Received: %s", readFromBuffer(buf))
+	}
+}
@@ -0,0 +1,258 @@
+package logg
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// A Formatter converts a log entry to the output representation.
+// Format must append the entry to dst and return the extended buffer.
+// A new line is added by the logger after formatting.
+//
+// Entry and all its fields are valid only during the Format call.
+type Formatter interface {
+	Format(dst []byte, e *Entry) []byte
+}
+
+// An EmptyFormatter is a Formatter which is called for messages without
+// text and fields too. Other formatters aren't called for them, an empty
+// line is written instead.
+type EmptyFormatter interface {
+	Formatter
+	FormatsEmpty()
+}
+
+// Entry contains all information about a log message passed to a Formatter.
+type Entry struct {
+	Time    time.Time // zero if flags do not contain date or time
+	Level   Level
+	File    string // empty if flags do not contain Lshortfile or Llongfile
+	Line    int
+	PC      uintptr // program counter of the caller, use runtime.FuncForPC to get the function
+	Message []byte
+	Fields  []Field // fields attached with With followed by message fields
+
+	Flags int
+	Color bool
+
+	bound *bound
+}
+
+// boundFields returns pre-encoded fields attached with With
+// and the rest of entry fields.
+func (e *Entry) boundFields() (*bound, []Field) {
+	if e.bound == nil {
+		return nil, e.Fields
+	}
+
+	return e.bound, e.Fields[len(e.bound.fields):]
+}
+
+var (
+	formatsMu sync.Mutex
+	formats   = newFormats() // []Formatter, index is a Format
+)
+
+// newFormats returns built-in formatters. It is called in the variable
+// declaration, so RegisterFormat can be used in package variables.
+func newFormats() *atomic.Value {
+	v := &atomic.Value{}
+	v.Store([]Formatter{
+		Pretty: prettyFormatter{},
+		Json:   jsonFormatter{},
+		Logfmt: logfmtFormatter{},
+	})
+
+	return v
+}
+
+// RegisterFormat adds a custom formatter and returns the format
+// which can be used in SetFormat.
+func RegisterFormat(f Formatter) Format {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	list := formats.Load().([]Formatter)
+	updated := make([]Formatter, len(list), len(list)+1)
+	copy(updated, list)
+	updated = append(updated, f)
+	formats.Store(updated)
+
+	return Format(len(list))
+}
+
+// Formatter returns the formatter of the format.
+// Unknown formats fall back to the Pretty formatter.
+func (f Format) Formatter() Formatter {
+	list := formats.Load().([]Formatter)
+	if f < 0 || int(f) >= len(list) {
+		return list[Pretty]
+	}
+
+	return list[f]
+}
+
+type prettyFormatter struct{}
+
+func (prettyFormatter) Format(dst []byte, e *Entry) []byte {
+	start := len(dst)
+
+	if e.Flags&(Ldate|Ltime|Lmicroseconds) != 0 {
+		if e.Color {
+			dst = append(dst, timeColor...)
+			dst = appendTimestamp(e.Time, Pretty, e.Flags, dst)
+			dst = append(dst, escapeClose...)
+		} else {
+			dst = appendTimestamp(e.Time, Pretty, e.Flags, dst)
+		}
+	}
+
+	if e.Flags&(Lshortfile|Llongfile) != 0 {
+		if len(dst) != start && dst[len(dst)-1] != ' ' {
+			dst = append(dst, ' ')
+		}
+
+		dst = append(dst, e.File...)
+		dst = append(dst, ':')
+		dst = strconv.AppendInt(dst, int64(e.Line), 10)
+	}
+
+	if e.Level != Empty {
+		if e.Flags&(Ldate|Ltime|Lmicroseconds) != 0 || e.Flags&(Lshortfile|Llongfile) != 0 {
+			dst = append(dst, ' ')
+		}
+
+		info := e.Level.info()
+		if e.Color {
+			dst = append(dst, info.color...)
+			dst = append(dst, escape+"[1m"...)
+		}
+
+		dst = append(dst, info.short...)
+
+		if e.Color {
+			dst = append(dst, escapeClose...)
+			dst = append(dst, escapeClose...)
+		}
+	}
+
+	if len(e.Message) != 0 {
+		if len(dst) != start && dst[len(dst)-1] != ' ' {
+			dst = append(dst, ' ')
+		}
+
+		dst = append(dst, e.Message...)
+	}
+
+	b, fields := e.boundFields()
+	if b != nil && len(b.pretty) != 0 {
+		if len(dst) != start && dst[len(dst)-1] != ' ' {
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, b.pretty...)
+	}
+
+	return appendPrettyFields(dst, fields)
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(dst []byte, e *Entry) []byte {
+	js := newJson()
+
+	if e.Flags&(Ldate|Ltime|Lmicroseconds) != 0 {
+		js.buf = append(js.addField("time", js.buf), '"')
+		js.buf = appendTimestamp(e.Time, Json, e.Flags, js.buf)
+		js.buf = append(js.buf, '"')
+	}
+
+	if e.Flags&(Lshortfile|Llongfile) != 0 {
+		js.buf = appendJSONString(js.addField("file", js.buf), e.File)
+		js.buf = strconv.AppendInt(js.addField("line", js.buf), int64(e.Line), 10)
+	}
+
+	if e.Level != Empty {
+		js.buf = appendJSONString(js.addField("level", js.buf), e.Level.info().name)
+	}
+
+	if len(e.Message) != 0 {
+		js.buf = appendJSONBytes(js.addField("message", js.buf), e.Message)
+	}
+
+	b, fields := e.boundFields()
+	if b != nil && len(b.json) != 0 {
+		if len(js.buf) > 1 {
+			js.buf = append(js.buf, ", "...)
+		}
+		js.buf = append(js.buf, b.json...)
+	}
+
+	js.appendFields(fields)
+	js.close()
+
+	dst = append(dst, js.buf...)
+	js.put()
+
+	return dst
+}
+
+type logfmtFormatter struct{}
+
+func (logfmtFormatter) Format(dst []byte, e *Entry) []byte {
+	start := len(dst)
+
+	if e.Flags&(Ldate|Ltime|Lmicroseconds) != 0 {
+		dst = append(dst, "time="...)
+		dst = appendTimestamp(e.Time, Logfmt, e.Flags, dst)
+	}
+
+	if e.Level != Empty {
+		if len(dst) != start {
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, "level="...)
+		dst = append(dst, e.Level.info().logfmt...)
+	}
+
+	if e.Flags&(Lshortfile|Llongfile) != 0 {
+		if len(dst) != start {
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, "caller="...)
+		if needsQuote(e.File) {
+			// quote the whole file:line value
+			dst = strconv.AppendQuote(dst, e.File)
+			dst = append(dst[:len(dst)-1], ':')
+			dst = strconv.AppendInt(dst, int64(e.Line), 10)
+			dst = append(dst, '"')
+		} else {
+			dst = append(dst, e.File...)
+			dst = append(dst, ':')
+			dst = strconv.AppendInt(dst, int64(e.Line), 10)
+		}
+	}
+
+	if len(e.Message) != 0 {
+		if len(dst) != start {
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, "msg="...)
+		dst = appendTextValue(dst, *(*string)(unsafe.Pointer(&e.Message)))
+	}
+
+	b, fields := e.boundFields()
+	if b != nil && len(b.pretty) != 0 {
+		if len(dst) != start {
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, b.pretty...)
+	}
+
+	if len(fields) != 0 && len(dst) != start {
+		dst = append(dst, ' ')
+	}
+	return appendPrettyFields(dst, fields)
+}
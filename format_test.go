@@ -0,0 +1,138 @@
+package logg
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testFormatter struct{}
+
+func (testFormatter) Format(dst []byte, e *Entry) []byte {
+	dst = append(dst, e.Level.String()...)
+	dst = append(dst, '|')
+	dst = append(dst, e.Message...)
+	for _, f := range e.Fields {
+		dst = append(dst, '|')
+		dst = append(dst, f.Key...)
+		dst = append(dst, ':')
+		dst = f.AppendText(dst)
+	}
+	return dst
+}
+
+func TestRegisterFormat(t *testing.T) {
+	custom := RegisterFormat(testFormatter{})
+	if custom <= Json {
+		t.Fatalf("custom format must not override built-in formats, received: %d", custom)
+	}
+
+	if _, ok := custom.Formatter().(testFormatter); !ok {
+		t.Errorf("wrong formatter for registered format: %T", custom.Formatter())
+	}
+	if _, ok := Format(-1).Formatter().(prettyFormatter); !ok {
+		t.Errorf("unknown format must fall back to pretty formatter, received: %T", Format(-1).Formatter())
+	}
+
+	buf := new(bytes.Buffer)
+	logger := New(buf).With(String("service", "api"))
+	logger.SetFormat(custom)
+
+	logger.Warnw("test", "id", 1)
+	expected := "WRN|test|service:api|id:1"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong custom output. Expected: %s, received: %s", expected, output)
+	}
+}
+
+func TestField_Value(t *testing.T) {
+	fields := []Field{String("k", "v"), Int("k", -1), Bool("k", true), Float64("k", 1.5)}
+	values := []interface{}{"v", int64(-1), true, 1.5}
+
+	for i, f := range fields {
+		if f.Value() != values[i] {
+			t.Errorf("wrong field value. Expected: %v, received: %v", values[i], f.Value())
+		}
+	}
+}
+
+func TestLogfmt(t *testing.T) {
+	tests := map[string]struct {
+		level  Level
+		flags  int
+		msg    string
+		kv     []interface{}
+		output string
+	}{
+		"message": {
+			level:  Info,
+			msg:    "test",
+			output: "level=info msg=test",
+		},
+		"quoted message": {
+			level:  Warning,
+			msg:    `say "hi" a=b`,
+			output: `level=warn msg="say \"hi\" a=b"`,
+		},
+		"fields": {
+			level:  Error,
+			msg:    "test",
+			kv:     []interface{}{"user", "bob smith", "latency", 1.5, "empty", ""},
+			output: `level=error msg=test user="bob smith" latency=1.5 empty=""`,
+		},
+		"caller": {
+			level:  Info,
+			flags:  Lshortfile,
+			msg:    "test",
+			output: "level=info caller=format_test.go:$1 msg=test",
+		},
+		"invalid keys": {
+			level:  Info,
+			msg:    "test",
+			kv:     []interface{}{"bad key", 1, "a=b", 2, `q"`, 3, Group("g r", Int("x\ny", 4))},
+			output: `level=info msg=test bad_key=1 a_b=2 q_=3 g_r.x_y=4`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := New(buf)
+			logger.SetFormat(Logfmt)
+			logger.SetFlags(tc.flags)
+
+			logger.write(0, tc.level, []byte(tc.msg), tc.kv...)
+			_, _, line, _ := runtime.Caller(0)
+			expected := strings.Replace(tc.output, "$1", strconv.Itoa(line-1), 1)
+
+			if output := readFromBuffer(buf); output != expected {
+				t.Errorf("wrong logfmt output. Expected: %s, received: %s", expected, output)
+			}
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	quoted := New(buf)
+	quoted.SetFormat(Logfmt)
+	quoted.SetFlags(Lshortfile)
+	quoted.Freeze(time.Time{}, "a b.go", 12)
+	quoted.Info("test")
+	if output := readFromBuffer(buf); output != `level=info caller="a b.go:12" msg=test` {
+		t.Errorf("caller must be quoted: %s", output)
+	}
+
+	logger := New(buf).With(String("service", "api"))
+	logger.SetFormat(Logfmt)
+	logger.SetFlags(LstdFlags | LUTC)
+	now := time.Now().UTC()
+	logger.Info("test")
+
+	expected := fmt.Sprintf("time=%s level=info msg=test service=api", now.Format("2006-01-02T15:04:05Z"))
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong logfmt output. Expected: %s, received: %s", expected, output)
+	}
+}
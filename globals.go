@@ -1,11 +1,22 @@
 package logg
 
 import (
+	"math"
 	"os"
+	"sync/atomic"
 )
 
-// Global Logg configuration
-var logg = New(os.Stderr)
+// Global Logg configuration, replaced by NewGlobal and NewGlobalTest
+var logg = newGlobal(New(os.Stderr))
+
+// newGlobal returns the global logger holder. It is called in the variable
+// declaration, so the global logger can be used in package variables.
+func newGlobal(l *Logg) *atomic.Pointer[Logg] {
+	p := &atomic.Pointer[Logg]{}
+	p.Store(l)
+
+	return p
+}
 
 // Default parameters
 const (
@@ -45,31 +56,47 @@ const (
 
 // Base types
 type (
-	format int
-	level  int
+	// Format defines an output format. Use RegisterFormat to add a custom format.
+	Format int
+	// Level defines a message severity. A bigger value means a more severe level.
+	Level int
 )
 
 var (
-	levels    = []string{"DBG", "INF", "ERR", "WRN", "PNC", "DEBUG", "INFO", "ERROR", "WARN", "PANIC"}
-	colors    = [][]byte{generate(HiCyan), generate(HiYellow), generate(Red), generate(HiGreen), generate(Red)}
+	builtinLevels = []LevelInfo{ // ordered by severity
+		{Level: Trace, Short: "TRC", Long: "TRACE", Color: HiBlack},
+		{Level: Debug, Short: "DBG", Long: "DEBUG", Color: HiCyan},
+		{Level: Info, Short: "INF", Long: "INFO", Color: HiYellow},
+		{Level: Warning, Short: "WRN", Long: "WARN", Color: HiGreen},
+		{Level: Error, Short: "ERR", Long: "ERROR", Color: Red},
+		{Level: Panic, Short: "PNC", Long: "PANIC", Color: Red},
+		{Level: Fatal, Short: "FTL", Long: "FATAL", Color: Red},
+	}
 	timeColor = generate(7)
+
+	// legacyLevels maps level values used before the severity order to levels.
+	legacyLevels = map[int]Level{-2: Trace, -1: Empty, 0: Debug, 1: Info, 2: Error, 3: Warning, 4: Panic, 5: Fatal}
 )
 
 // Output formats
 const (
-	Pretty format = iota
+	Pretty Format = iota
 	Json
+	Logfmt
 )
 
-// Log levels.
+// Log levels ordered by severity. Values are stable and leave
+// space between levels, so a level can be compared with any number.
 const (
-	Debug level = iota
-	Info
-	Error
-	Warning
-	Panic
+	Trace   Level = -8
+	Debug   Level = -4
+	Info    Level = 0
+	Warning Level = 4
+	Error   Level = 8
+	Panic   Level = 12
+	Fatal   Level = 16
 
-	Empty level = -1
+	Empty Level = math.MinInt32 // message without level
 )
 
 // Base colors for console
@@ -22,28 +22,60 @@ func write(w io.Writer, b []byte) error {
 	return nil
 }
 
-func caller(calldepth int, shortFile bool) (file string, line int) {
-	_, file, line, ok := runtime.Caller(calldepth)
+// flush flushes buffered data of the writer if it supports it.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Sync() error }:
+		return f.Sync()
+	}
+
+	return nil
+}
+
+// caller returns the program counter, the file and the line of the caller.
+func caller(calldepth int, shortFile bool) (pc uintptr, file string, line int) {
+	pc, file, line, ok := runtime.Caller(calldepth)
 	if !ok {
 		file = "???"
 		line = 0
 	}
 
 	if shortFile {
-		short := file
-		for i := len(file) - 1; i > 0; i-- {
-			if file[i] == '/' {
-				short = file[i+1:]
-				break
-			}
-		}
-		file = short
+		file = shortName(file)
 	}
 
 	return
 }
 
-func appendTimestamp(t time.Time, format format, flags int, dst []byte) []byte {
+// callerPC returns the file and the line of the program counter.
+func callerPC(pc uintptr, shortFile bool) (file string, line int) {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	file, line = frame.File, frame.Line
+	if file == "" {
+		file = "???"
+	}
+
+	if shortFile {
+		file = shortName(file)
+	}
+
+	return
+}
+
+// shortName returns the final element of the file path.
+func shortName(file string) string {
+	for i := len(file) - 1; i > 0; i-- {
+		if file[i] == '/' {
+			return file[i+1:]
+		}
+	}
+
+	return file
+}
+
+func appendTimestamp(t time.Time, format Format, flags int, dst []byte) []byte {
 	if t.IsZero() {
 		return append(dst, "0000-00-00 00:00:00"...)
 	}
@@ -64,7 +96,7 @@ func appendTimestamp(t time.Time, format format, flags int, dst []byte) []byte {
 	micro := t.Nanosecond() / 1000
 
 	var space byte = ' '
-	if format == Json {
+	if format == Json || format == Logfmt {
 		space = 'T'
 	}
 
@@ -114,17 +146,21 @@ func appendTimestamp(t time.Time, format format, flags int, dst []byte) []byte {
 		}...)
 	}
 
-	if format == Json {
+	if format == Json || format == Logfmt {
 		_, s := t.Zone()
 		if s == 0 {
 			dst = append(dst, 'Z')
 		} else {
+			sign := byte('+')
+			if s < 0 {
+				sign, s = '-', -s
+			}
+			hours, minutes := s/3600, s/60%60
 			dst = append(dst, []byte{
-				'+',
-				digits10[s/3600], digits01[s/3600],
+				sign,
+				digits10[hours], digits01[hours],
 				':',
-				'0',
-				'0',
+				digits10[minutes], digits01[minutes],
 			}...)
 		}
 	}
@@ -132,43 +168,37 @@ func appendTimestamp(t time.Time, format format, flags int, dst []byte) []byte {
 	return dst
 }
 
-func defineLevel(data *[]byte) (lvl level) {
+func defineLevel(data *[]byte) (lvl Level) {
 	lvl = Empty
 
 	if len(*data) == 0 {
 		return
 	}
 
+	table := loadLevels()
+
 	leftPadding := 0
 	rightPadding := 2
-	maxRightPadding := 7
-	if maxRightPadding > len(*data) {
-		maxRightPadding = len(*data)
-	}
-
 	if (*data)[0] == '[' {
 		leftPadding = 1
 		rightPadding = 3
 	}
 
+	maxRightPadding := leftPadding + table.maxLabel + 1
+	if maxRightPadding > len(*data) {
+		maxRightPadding = len(*data)
+	}
+
 	for lvl == Empty && rightPadding < maxRightPadding {
 		searchPart := (*data)[leftPadding:rightPadding]
-		for i := 0; i < len(levels); i++ {
-			if levels[i] == *(*string)(unsafe.Pointer(&searchPart)) {
-				if i > 4 {
-					i -= 5
-				}
-				lvl = level(i)
-				break
-			}
-		}
+		lvl = table.lookup(*(*string)(unsafe.Pointer(&searchPart)))
 		rightPadding++
 	}
 
 	return
 }
 
-func removeLevel(data []byte, lvl level) []byte {
+func removeLevel(data []byte, lvl Level) []byte {
 	if len(data) == 0 || lvl == Empty {
 		return data
 	}
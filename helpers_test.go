@@ -9,7 +9,7 @@ import (
 )
 
 func Test_caller(t *testing.T) {
-	file, line := caller(60, false)
+	_, file, line := caller(60, false)
 	if file != "???" {
 		t.Errorf("caller path must be undefined. Received: %s", file)
 	}
@@ -17,7 +17,7 @@ func Test_caller(t *testing.T) {
 		t.Errorf("caller line must be 0. Received: %d", line)
 	}
 
-	file, line = caller(1, false)
+	pc, file, line := caller(1, false)
 	_, f, l, _ := runtime.Caller(0)
 	if file != f {
 		t.Errorf("wrong caller path. Expected: %s, received: %s", f, file)
@@ -25,8 +25,11 @@ func Test_caller(t *testing.T) {
 	if line != l-1 {
 		t.Errorf("wrong caller line. Expected: %d, received: %d", l-1, line)
 	}
+	if name := runtime.FuncForPC(pc).Name(); name != "github.com/pkgz/logg.Test_caller" {
+		t.Errorf("wrong caller function: %s", name)
+	}
 
-	file, line = caller(1, true)
+	_, file, line = caller(1, true)
 	_, f, l, _ = runtime.Caller(0)
 	if !strings.Contains(f, file) {
 		t.Errorf("wrong caller path. Expected: %s, received: %s", f, file)
@@ -37,11 +40,11 @@ func Test_caller(t *testing.T) {
 }
 
 func Test_appendTimestamp(t *testing.T) {
-	now := time.Now()
+	now := time.Date(2020, 1, 2, 3, 4, 5, 607459800, time.Local)
 	tests := map[string]struct {
 		t      time.Time
 		flags  int
-		format format
+		format Format
 		buf    []byte
 	}{
 		"empty": {
@@ -52,32 +55,50 @@ func Test_appendTimestamp(t *testing.T) {
 		"default format": {
 			t:     now,
 			flags: LstdFlags,
-			buf:   []byte(now.Format("2006-01-02 15:04:05")),
+			buf:   []byte("2020-01-02 03:04:05"),
 		},
 		"date only": {
 			t:     now,
 			flags: Ldate,
-			buf:   []byte(now.Format("2006-01-02")),
+			buf:   []byte("2020-01-02"),
 		},
 		"time only": {
 			t:     now,
 			flags: Ltime,
-			buf:   []byte(now.Format("15:04:05")),
+			buf:   []byte("03:04:05"),
 		},
 		"milliseconds only": {
 			t:     now,
 			flags: Lmicroseconds,
-			buf:   []byte(now.Add(time.Nanosecond * 500).Format(".999999")),
+			buf:   []byte(".607460"),
 		},
 		"time with milliseconds": {
 			t:     now,
 			flags: Ltime | Lmicroseconds,
-			buf:   []byte(now.Add(time.Nanosecond * 500).Format("15:04:05.999999")),
+			buf:   []byte("03:04:05.607460"),
 		},
 		"date with time with milliseconds": {
 			t:     now,
 			flags: Ldate | Ltime | Lmicroseconds,
-			buf:   []byte(now.Add(time.Nanosecond * 500).Format("2006-01-02 15:04:05.999999")),
+			buf:   []byte("2020-01-02 03:04:05.607460"),
+		},
+		"json utc": {
+			t:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			flags:  LstdFlags,
+			format: Json,
+			buf:    []byte("2020-01-02T03:04:05Z"),
+		},
+		"json negative zone": {
+			t:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600)),
+			flags:  LstdFlags,
+			format: Json,
+			buf:    []byte("2020-01-02T03:04:05-05:00"),
+		},
+		"logfmt half-hour zone": {
+			t:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("IST", 5*3600+30*60)),
+			flags:  LstdFlags,
+			format: Logfmt,
+			buf:    []byte("2020-01-02T03:04:05+05:30"),
 		},
 	}
 
@@ -109,7 +130,7 @@ func Test_defineLevel(t *testing.T) {
 	tests := map[string]struct {
 		data   []byte
 		result []byte
-		level  level
+		level  Level
 	}{
 		"empty": {
 			data:   []byte{},
@@ -202,6 +223,26 @@ func Test_defineLevel(t *testing.T) {
 			result: []byte("test"),
 			level:  Panic,
 		},
+		"trace short": {
+			data:   []byte("TRC test"),
+			result: []byte("test"),
+			level:  Trace,
+		},
+		"trace long": {
+			data:   []byte("[TRACE] test"),
+			result: []byte("test"),
+			level:  Trace,
+		},
+		"fatal short": {
+			data:   []byte("[FTL] test"),
+			result: []byte("test"),
+			level:  Fatal,
+		},
+		"fatal long": {
+			data:   []byte("FATAL test"),
+			result: []byte("test"),
+			level:  Fatal,
+		},
 	}
 
 	for name, tc := range tests {
@@ -0,0 +1,259 @@
+package logg
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AccessFormat defines an access log line format.
+type AccessFormat int
+
+// Access log formats
+const (
+	AccessStructured AccessFormat = iota // "request" message with fields
+	AccessCommon                         // Apache Common Log Format
+	AccessCombined                       // Apache/NGINX Combined Log Format
+)
+
+const (
+	// DefaultRequestIDHeader is a header with the request ID.
+	DefaultRequestIDHeader = "X-Request-Id"
+
+	clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+)
+
+// AccessLogConfig defines the access log middleware.
+type AccessLogConfig struct {
+	Format          AccessFormat
+	RequestIDHeader string // DefaultRequestIDHeader by default
+}
+
+// AccessLog returns a middleware which writes a line for each request.
+// The level depends on the response status: Error for 5xx, Warning for 4xx
+// and Info for others. Fields registered for the request context are
+// added to each line.
+//
+//	http.ListenAndServe(":8080", logg.AccessLog(log, logg.AccessLogConfig{})(mux))
+func AccessLog(l *Logg, cfg AccessLogConfig) func(http.Handler) http.Handler {
+	if cfg.RequestIDHeader == "" {
+		cfg.RequestIDHeader = DefaultRequestIDHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := l.now()
+			rw := &responseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(wrapResponseWriter(rw), r)
+
+			l.writeAccess(cfg, r, rw, start)
+		})
+	}
+}
+
+// accessLevel returns the level of a response with the status.
+func accessLevel(status int) Level {
+	switch {
+	case status >= 500:
+		return Error
+	case status >= 400:
+		return Warning
+	}
+
+	return Info
+}
+
+func (l *Logg) writeAccess(cfg AccessLogConfig, r *http.Request, rw *responseWriter, start time.Time) {
+	latency := l.now().Sub(start)
+	status := rw.statusCode()
+	level := accessLevel(status)
+	requestID := r.Header.Get(cfg.RequestIDHeader)
+
+	if cfg.Format == AccessStructured {
+		kv := []interface{}{
+			String("method", r.Method),
+			String("path", r.URL.Path),
+			Int("status", status),
+			Int64("bytes", rw.bytes),
+			Duration("latency", latency),
+			String("remote", remoteHost(r.RemoteAddr)),
+			String("user_agent", r.UserAgent()),
+		}
+		if requestID != "" {
+			kv = append(kv, String("request_id", requestID))
+		}
+
+		l.writeContext(r.Context(), 1, level, []byte("request"), kv)
+		return
+	}
+
+	msg := make([]byte, 0, 256)
+	msg = append(msg, remoteHost(r.RemoteAddr)...)
+	msg = append(msg, " - "...)
+	msg = appendCLFValue(msg, requestUser(r))
+	msg = append(msg, " ["...)
+	msg = start.AppendFormat(msg, clfTimeFormat)
+	msg = append(msg, `] "`...)
+	msg = append(msg, r.Method...)
+	msg = append(msg, ' ')
+	msg = appendCLFValue(msg, r.RequestURI)
+	msg = append(msg, ' ')
+	msg = append(msg, r.Proto...)
+	msg = append(msg, `" `...)
+	msg = strconv.AppendInt(msg, int64(status), 10)
+	msg = append(msg, ' ')
+	if rw.bytes == 0 {
+		msg = append(msg, '-')
+	} else {
+		msg = strconv.AppendInt(msg, rw.bytes, 10)
+	}
+
+	if cfg.Format == AccessCombined {
+		msg = append(msg, ` "`...)
+		msg = appendCLFValue(msg, r.Referer())
+		msg = append(msg, `" "`...)
+		msg = appendCLFValue(msg, r.UserAgent())
+		msg = append(msg, '"')
+	}
+
+	var kv []interface{}
+	if requestID != "" {
+		kv = []interface{}{String("request_id", requestID)}
+	}
+
+	l.writeContext(r.Context(), 1, level, msg, kv)
+}
+
+// remoteHost returns the host of the remote address.
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// requestUser returns the user of the basic authentication or the URL.
+func requestUser(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	if r.URL.User != nil {
+		return r.URL.User.Username()
+	}
+
+	return ""
+}
+
+// appendCLFValue appends the value or "-" if it's empty. Quotes, backslashes,
+// control and non-ASCII bytes are escaped as \xHH like NGINX does, so
+// a client can't forge fields of the line.
+func appendCLFValue(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, '-')
+	}
+
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' || c == '\\' || c < 0x20 || c >= 0x7f {
+			dst = append(dst, '\\', 'x', hex[c>>4], hex[c&0xf])
+			continue
+		}
+		dst = append(dst, c)
+	}
+
+	return dst
+}
+
+// responseWriter records the status and the number of written bytes.
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	bytes    int64
+	hijacked bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+// Unwrap returns the original writer, it's used by http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) statusCode() int {
+	switch {
+	case w.status != 0:
+		return w.status
+	case w.hijacked:
+		return http.StatusSwitchingProtocols
+	}
+
+	return http.StatusOK
+}
+
+func (w *responseWriter) flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+
+	return conn, rw, err
+}
+
+type flushWriter struct{ *responseWriter }
+
+func (w flushWriter) Flush() { w.flush() }
+
+type hijackWriter struct{ *responseWriter }
+
+func (w hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+type flushHijackWriter struct{ *responseWriter }
+
+func (w flushHijackWriter) Flush() { w.flush() }
+
+func (w flushHijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+// wrapResponseWriter returns a writer which implements the same optional
+// interfaces (http.Flusher, http.Hijacker) as the original writer.
+func wrapResponseWriter(w *responseWriter) http.ResponseWriter {
+	_, flusher := w.ResponseWriter.(http.Flusher)
+	_, hijacker := w.ResponseWriter.(http.Hijacker)
+
+	switch {
+	case flusher && hijacker:
+		return flushHijackWriter{w}
+	case flusher:
+		return flushWriter{w}
+	case hijacker:
+		return hijackWriter{w}
+	}
+
+	return w
+}
@@ -0,0 +1,167 @@
+package logg
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAccessLog(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte("hello"))
+		}
+	})
+
+	tests := map[string]struct {
+		format   AccessFormat
+		path     string
+		expected string
+	}{
+		"structured": {
+			format:   AccessStructured,
+			path:     "/hello?a=1",
+			expected: `^INF request method=GET path=/hello status=200 bytes=5 latency=0s remote=192\.0\.2\.1 user_agent=test request_id=abc$`,
+		},
+		"structured not found": {
+			format:   AccessStructured,
+			path:     "/missing",
+			expected: `^WRN request method=GET path=/missing status=404 bytes=19 `,
+		},
+		"common": {
+			format:   AccessCommon,
+			path:     "/hello?a=1",
+			expected: `^INF 192\.0\.2\.1 - bob \[10/Oct/2000:13:55:36 \+0000\] "GET /hello\?a=1 HTTP/1\.1" 200 5 request_id=abc$`,
+		},
+		"combined": {
+			format:   AccessCombined,
+			path:     "/fail",
+			expected: `^ERR 192\.0\.2\.1 - bob \[.+\] "GET /fail HTTP/1\.1" 500 - "http://example\.com/" "test" request_id=abc$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := New(buf)
+			logger.SetFlags(0)
+			logger.ToggleColor(false)
+			logger.Freeze(time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC), "", 0)
+
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.Header.Set("User-Agent", "test")
+			r.Header.Set("Referer", "http://example.com/")
+			r.Header.Set("X-Request-Id", "abc")
+			r.SetBasicAuth("bob", "secret")
+
+			AccessLog(logger, AccessLogConfig{Format: tc.format})(handler).ServeHTTP(httptest.NewRecorder(), r)
+
+			if output := readFromBuffer(buf); !regexp.MustCompile(tc.expected).MatchString(output) {
+				t.Errorf("wrong output.\nExpected: %s\nReceived: %s", tc.expected, output)
+			}
+		})
+	}
+}
+
+func TestAccessLog_escape(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+	logger.Freeze(time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC), "", 0)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RequestURI = `/a"b`
+	r.Header.Set("User-Agent", `evil" 200 "x`)
+	r.Header.Set("Referer", "http://example.com/\\\n")
+	r.SetBasicAuth("bob smith", "secret")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	AccessLog(logger, AccessLogConfig{Format: AccessCombined})(handler).ServeHTTP(httptest.NewRecorder(), r)
+
+	expected := `INF 192.0.2.1 - bob smith [10/Oct/2000:13:55:36 +0000] "GET /a\x22b HTTP/1.1" 200 - "http://example.com/\x5C\x0A" "evil\x22 200 \x22x"`
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong output.\nExpected: %s\nReceived: %s", expected, output)
+	}
+}
+
+func Test_accessLevel(t *testing.T) {
+	tests := map[int]Level{200: Info, 301: Info, 400: Warning, 499: Warning, 500: Error, 503: Error}
+
+	for status, expected := range tests {
+		if level := accessLevel(status); level != expected {
+			t.Errorf("wrong level of %d. Expected: %s, received: %s", status, expected, level)
+		}
+	}
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+type plainWriter struct {
+	http.ResponseWriter
+}
+
+type plainHijacker struct {
+	plainWriter
+}
+
+func (plainHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+func Test_wrapResponseWriter(t *testing.T) {
+	tests := map[string]struct {
+		w        http.ResponseWriter
+		flusher  bool
+		hijacker bool
+	}{
+		"plain":            {w: plainWriter{httptest.NewRecorder()}},
+		"flusher":          {w: httptest.NewRecorder(), flusher: true},
+		"hijacker":         {w: plainHijacker{plainWriter{httptest.NewRecorder()}}, hijacker: true},
+		"flusher hijacker": {w: hijackRecorder{httptest.NewRecorder()}, flusher: true, hijacker: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := &responseWriter{ResponseWriter: tc.w}
+			w := wrapResponseWriter(rw)
+
+			if _, ok := w.(http.Flusher); ok != tc.flusher {
+				t.Errorf("wrong flusher implementation: %v", ok)
+			}
+			if h, ok := w.(http.Hijacker); ok != tc.hijacker {
+				t.Errorf("wrong hijacker implementation: %v", ok)
+			} else if ok {
+				_, _, _ = h.Hijack()
+				if rw.statusCode() != http.StatusSwitchingProtocols {
+					t.Errorf("wrong status of a hijacked connection: %d", rw.statusCode())
+				}
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+				if rw.statusCode() != http.StatusOK {
+					t.Errorf("wrong status after flush: %d", rw.statusCode())
+				}
+			}
+			if u, ok := w.(interface{ Unwrap() http.ResponseWriter }); !ok || u.Unwrap() != tc.w {
+				t.Error("writer must be unwrapped to the original writer")
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package logg
 import (
 	"fmt"
 	"io"
+	"os"
+	"time"
 )
 
 // PRINT
@@ -15,6 +17,22 @@ func (l *Logg) Printf(format string, args ...interface{}) {
 	l.write(1, Empty, []byte(fmt.Sprintf(format, args...)))
 }
 
+func (l *Logg) Printw(msg string, keysAndValues ...interface{}) {
+	l.write(1, Empty, []byte(msg), keysAndValues...)
+}
+
+func (l *Logg) Trace(args ...interface{}) {
+	l.write(1, Trace, []byte(fmt.Sprint(args...)))
+}
+
+func (l *Logg) Tracef(format string, args ...interface{}) {
+	l.write(1, Trace, []byte(fmt.Sprintf(format, args...)))
+}
+
+func (l *Logg) Tracew(msg string, keysAndValues ...interface{}) {
+	l.write(1, Trace, []byte(msg), keysAndValues...)
+}
+
 func (l *Logg) Debug(args ...interface{}) {
 	l.write(1, Debug, []byte(fmt.Sprint(args...)))
 }
@@ -23,6 +41,10 @@ func (l *Logg) Debugf(format string, args ...interface{}) {
 	l.write(1, Debug, []byte(fmt.Sprintf(format, args...)))
 }
 
+func (l *Logg) Debugw(msg string, keysAndValues ...interface{}) {
+	l.write(1, Debug, []byte(msg), keysAndValues...)
+}
+
 func (l *Logg) Info(args ...interface{}) {
 	l.write(1, Info, []byte(fmt.Sprint(args...)))
 }
@@ -31,14 +53,22 @@ func (l *Logg) Infof(format string, args ...interface{}) {
 	l.write(1, Info, []byte(fmt.Sprintf(format, args...)))
 }
 
+func (l *Logg) Infow(msg string, keysAndValues ...interface{}) {
+	l.write(1, Info, []byte(msg), keysAndValues...)
+}
+
 func (l *Logg) Error(args ...interface{}) {
-	l.write(0, Error, []byte(fmt.Sprint(args...)))
+	l.write(1, Error, []byte(fmt.Sprint(args...)))
 }
 
 func (l *Logg) Errorf(format string, args ...interface{}) {
 	l.write(1, Error, []byte(fmt.Sprintf(format, args...)))
 }
 
+func (l *Logg) Errorw(msg string, keysAndValues ...interface{}) {
+	l.write(1, Error, []byte(msg), keysAndValues...)
+}
+
 func (l *Logg) Warn(args ...interface{}) {
 	l.write(1, Warning, []byte(fmt.Sprint(args...)))
 }
@@ -47,55 +77,193 @@ func (l *Logg) Warnf(format string, args ...interface{}) {
 	l.write(1, Warning, []byte(fmt.Sprintf(format, args...)))
 }
 
+func (l *Logg) Warnw(msg string, keysAndValues ...interface{}) {
+	l.write(1, Warning, []byte(msg), keysAndValues...)
+}
+
+// Panic writes a message and panics with it.
 func (l *Logg) Panic(args ...interface{}) {
-	l.write(1, Panic, []byte(fmt.Sprint(args...)))
+	msg := fmt.Sprint(args...)
+	l.write(1, Panic, []byte(msg))
+	l.panicMessage(msg)
 }
 
+// Panicf writes a message and panics with it.
 func (l *Logg) Panicf(format string, args ...interface{}) {
-	l.write(1, Panic, []byte(fmt.Sprintf(format, args...)))
+	msg := fmt.Sprintf(format, args...)
+	l.write(1, Panic, []byte(msg))
+	l.panicMessage(msg)
+}
+
+// Panicw writes a message with fields and panics with the message.
+func (l *Logg) Panicw(msg string, keysAndValues ...interface{}) {
+	l.write(1, Panic, []byte(msg), keysAndValues...)
+	l.panicMessage(msg)
+}
+
+// Fatal writes a message, flushes the writer and exits with status 1.
+func (l *Logg) Fatal(args ...interface{}) {
+	l.write(1, Fatal, []byte(fmt.Sprint(args...)))
+	l.fatal()
+}
+
+// Fatalf writes a message, flushes the writer and exits with status 1.
+func (l *Logg) Fatalf(format string, args ...interface{}) {
+	l.write(1, Fatal, []byte(fmt.Sprintf(format, args...)))
+	l.fatal()
+}
+
+// Fatalw writes a message with fields, flushes the writer and exits with status 1.
+func (l *Logg) Fatalw(msg string, keysAndValues ...interface{}) {
+	l.write(1, Fatal, []byte(msg), keysAndValues...)
+	l.fatal()
 }
 
 // SETTINGS
 
 func (l *Logg) DebugMode() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.flags = Ldate | Ltime | Lmicroseconds | Lshortfile
 	l.minLevel = Debug
 }
 
-func (l *Logg) SetFormat(format format) {
+func (l *Logg) SetFormat(format Format) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.format = format
 }
 
 func (l *Logg) SetFlags(flags int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.flags = flags
 }
 
 func (l *Logg) SetWriter(w io.Writer) {
-	l.out = w
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.out = l.syncWriter(w)
+}
+
+// SetLevelWriter sets a writer for messages with the level and above.
+// Messages below all levels with a writer and messages without
+// a level are written to the main writer. A nil writer removes the route.
+//
+//	log.SetWriter(os.Stdout)
+//	log.SetLevelWriter(logg.Error, os.Stderr) // errors and above to stderr
+func (l *Logg) SetLevelWriter(level Level, w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	routes := make([]route, 0, len(l.routes)+1)
+	for _, r := range l.routes {
+		if r.level != level {
+			routes = append(routes, r)
+		}
+	}
+
+	if w != nil {
+		out := l.syncWriter(w)
+
+		i := 0
+		for i < len(routes) && routes[i].level < level {
+			i++
+		}
+		routes = append(routes, route{})
+		copy(routes[i+1:], routes[i:])
+		routes[i] = route{level: level, out: out}
+	}
+
+	l.routes = routes
 }
 
 func (l *Logg) ToggleColor(value bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.color = value
 }
 
-func (l *Logg) MinLevel(level level) {
+func (l *Logg) MinLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.minLevel = level
 }
 
+// Level returns the minimum level of the logger.
+func (l *Logg) Level() Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.minLevel
+}
+
+// SetExitFunc sets a function which is called by Fatal instead of os.Exit.
+func (l *Logg) SetExitFunc(fn func(code int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if fn == nil {
+		fn = os.Exit
+	}
+	l.exit = fn
+}
+
+// SetClock sets a function which returns the time of messages.
+// A nil function restores time.Now.
+func (l *Logg) SetClock(fn func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.clock = fn
+}
+
+// Freeze sets the time and the caller of all messages, so the output
+// is deterministic and can be compared with golden files. A zero time
+// restores the clock, an empty file restores the caller.
+//
+//	log.Freeze(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "main.go", 10)
+func (l *Logg) Freeze(t time.Time, file string, line int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.clock = nil
+	if !t.IsZero() {
+		l.clock = func() time.Time { return t }
+	}
+
+	l.frame = nil
+	if file != "" {
+		l.frame = &frame{file: file, line: line}
+	}
+}
+
 // Global
 
-func Print(args ...interface{}) { logg.Print(args...) }
+func Print(args ...interface{}) { logg.Load().Print(args...) }
+
+func Printf(format string, args ...interface{}) { logg.Load().Printf(format, args...) }
+
+func DebugMode() { logg.Load().DebugMode() }
+
+func SetFormat(format Format) { logg.Load().SetFormat(format) }
 
-func Printf(format string, args ...interface{}) { logg.Printf(format, args...) }
+func SetFlags(flags int) { logg.Load().SetFlags(flags) }
 
-func DebugMode() { logg.DebugMode() }
+func SetWriter(w io.Writer) { logg.Load().SetWriter(w) }
 
-func SetFormat(format format) { logg.SetFormat(format) }
+func SetLevelWriter(level Level, w io.Writer) { logg.Load().SetLevelWriter(level, w) }
 
-func SetFlags(flags int) { logg.SetFlags(flags) }
+func AddSink(s Sink) { logg.Load().AddSink(s) }
 
-func SetWriter(w io.Writer) { logg.SetWriter(w) }
+func RemoveSink(w io.Writer) { logg.Load().RemoveSink(w) }
 
-func ToggleColor(value bool) { logg.ToggleColor(value) }
+func ToggleColor(value bool) { logg.Load().ToggleColor(value) }
 
-func MinLevel(level level) { logg.MinLevel(level) }
+func MinLevel(level Level) { logg.Load().MinLevel(level) }
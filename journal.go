@@ -0,0 +1,238 @@
+package logg
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// DefaultJournalSocket is a socket of the systemd journal native protocol.
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// JournalConfig defines a journald connection.
+type JournalConfig struct {
+	Address    string // socket path, DefaultJournalSocket by default
+	Identifier string // SYSLOG_IDENTIFIER, program name by default
+}
+
+// Journal is a Formatter which formats entries as journal fields and
+// an io.Writer which sends them to journald with the native protocol.
+// Each entry contains MESSAGE, PRIORITY, SYSLOG_IDENTIFIER, CODE_FILE,
+// CODE_LINE and CODE_FUNC (with Lshortfile or Llongfile flags) and fields.
+// Field keys are converted to journal field names (user.id → USER_ID),
+// keys which collide with well-known fields are prefixed (priority → F_PRIORITY).
+// Entries which don't fit in a datagram are sent in a temporary file.
+//
+//	j, err := logg.NewJournal(logg.JournalConfig{})
+//	log.AddSink(j.Sink(logg.Debug))
+type Journal struct {
+	cfg JournalConfig
+
+	mu   sync.Mutex
+	conn *net.UnixConn
+}
+
+// NewJournal connects to journald.
+func NewJournal(cfg JournalConfig) (*Journal, error) {
+	if cfg.Address == "" {
+		cfg.Address = DefaultJournalSocket
+	}
+	if cfg.Identifier == "" {
+		cfg.Identifier = filepath.Base(os.Args[0])
+	}
+
+	j := &Journal{cfg: cfg}
+	if err := j.connect(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Sink returns a sink which writes messages with the level and above to journald.
+func (j *Journal) Sink(level Level) Sink {
+	return Sink{Writer: j, Formatter: j, MinLevel: level}
+}
+
+// Format appends the entry as journal fields to dst.
+// The last field is not terminated, the logger adds a new line.
+func (j *Journal) Format(dst []byte, e *Entry) []byte {
+	dst = appendJournalField(dst, "PRIORITY", strconv.Itoa(syslogSeverity(e.Level)))
+	dst = appendJournalField(dst, "SYSLOG_IDENTIFIER", j.cfg.Identifier)
+
+	if e.Flags&(Lshortfile|Llongfile) != 0 {
+		dst = appendJournalField(dst, "CODE_FILE", e.File)
+		dst = appendJournalField(dst, "CODE_LINE", strconv.Itoa(e.Line))
+		if fn := runtime.FuncForPC(e.PC); fn != nil {
+			dst = appendJournalField(dst, "CODE_FUNC", fn.Name())
+		}
+	}
+
+	dst = appendJournalFields(dst, "", e.Fields)
+	dst = appendJournalField(dst, "MESSAGE", string(e.Message))
+
+	return dst[:len(dst)-1]
+}
+
+// FormatsEmpty marks the journal as an EmptyFormatter, so empty messages
+// are sent with PRIORITY and MESSAGE fields too.
+func (j *Journal) FormatsEmpty() {}
+
+// appendJournalFields appends fields, groups are flattened to PREFIX_KEY fields.
+func appendJournalFields(dst []byte, prefix string, fields []Field) []byte {
+	for _, f := range fields {
+		name := journalName(prefix, f.Key)
+
+		if f.typ == groupType {
+			p := prefix
+			if f.Key != "" {
+				p = name
+			}
+			dst = appendJournalFields(dst, p, f.val.([]Field))
+			continue
+		}
+
+		if s, ok := f.stringValue(); ok {
+			dst = appendJournalField(dst, name, s)
+		} else {
+			dst = appendJournalField(dst, name, string(f.appendValue(nil)))
+		}
+	}
+
+	return dst
+}
+
+// appendJournalField appends a field terminated by a new line. Values with
+// a new line are written as the name, a new line, the 64-bit little-endian
+// length and the value.
+func appendJournalField(dst []byte, name, value string) []byte {
+	dst = append(dst, name...)
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\n' {
+			dst = append(dst, '\n')
+			dst = binary.LittleEndian.AppendUint64(dst, uint64(len(value)))
+			dst = append(dst, value...)
+			return append(dst, '\n')
+		}
+	}
+
+	dst = append(dst, '=')
+	dst = append(dst, value...)
+
+	return append(dst, '\n')
+}
+
+// journalReserved are names of trusted and well-known journal fields,
+// fields with these names get the F_ prefix.
+var journalReserved = map[string]bool{
+	"MESSAGE": true, "MESSAGE_ID": true, "PRIORITY": true,
+	"CODE_FILE": true, "CODE_LINE": true, "CODE_FUNC": true,
+	"ERRNO": true, "INVOCATION_ID": true, "USER_INVOCATION_ID": true,
+	"SYSLOG_FACILITY": true, "SYSLOG_IDENTIFIER": true, "SYSLOG_PID": true,
+	"SYSLOG_TIMESTAMP": true, "SYSLOG_RAW": true, "DOCUMENTATION": true, "TID": true,
+}
+
+// journalName converts the key to a journal field name: uppercase letters,
+// digits and underscores, starting with a letter, up to 64 characters.
+// Reserved names are prefixed with F_.
+func journalName(prefix, key string) string {
+	b := make([]byte, 0, len(prefix)+len(key)+1)
+	if prefix != "" {
+		b = append(b, prefix...)
+		b = append(b, '_')
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			c = '_'
+		}
+
+		if c == '_' && len(b) == 0 {
+			continue // names with a leading underscore are reserved
+		}
+		b = append(b, c)
+	}
+
+	if len(b) == 0 || (b[0] >= '0' && b[0] <= '9') || journalReserved[string(b)] {
+		b = append([]byte{'F', '_'}, b...)
+	}
+	if len(b) > 64 {
+		b = b[:64]
+	}
+
+	return string(b)
+}
+
+// Write sends the entry to journald. If the entry doesn't fit in a datagram,
+// it is written to a temporary file which is passed to journald.
+// The connection is reestablished on write errors.
+func (j *Journal) Write(b []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.conn == nil {
+		if err := j.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	err := j.send(b)
+	if err != nil && !isMsgSize(err) {
+		_ = j.conn.Close()
+		if err = j.connect(); err != nil {
+			j.conn = nil
+			return 0, err
+		}
+		err = j.send(b)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close closes the connection.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.conn == nil {
+		return nil
+	}
+
+	err := j.conn.Close()
+	j.conn = nil
+
+	return err
+}
+
+// send sends the entry in a datagram or in a file if it's too long.
+func (j *Journal) send(b []byte) error {
+	_, err := j.conn.Write(b)
+	if isMsgSize(err) {
+		return sendJournalFile(j.conn, b)
+	}
+
+	return err
+}
+
+func (j *Journal) connect() error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: j.cfg.Address, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	j.conn = conn
+
+	return nil
+}
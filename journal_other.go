@@ -0,0 +1,18 @@
+//go:build !unix
+
+package logg
+
+import (
+	"errors"
+	"net"
+)
+
+// isMsgSize reports whether the datagram is too long.
+func isMsgSize(err error) bool {
+	return false
+}
+
+// sendJournalFile is not supported on this platform.
+func sendJournalFile(conn *net.UnixConn, b []byte) error {
+	return errors.New("logg: journal entry is too long")
+}
@@ -0,0 +1,203 @@
+//go:build unix
+
+package logg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// parseJournal parses fields of the journal native protocol.
+func parseJournal(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+
+	fields := map[string]string{}
+	for len(b) != 0 {
+		i := bytes.IndexAny(b, "=\n")
+		if i < 0 {
+			t.Fatalf("field is not terminated: %q", b)
+		}
+
+		name := string(b[:i])
+		if b[i] == '=' {
+			end := bytes.IndexByte(b, '\n')
+			fields[name] = string(b[i+1 : end])
+			b = b[end+1:]
+			continue
+		}
+
+		n := binary.LittleEndian.Uint64(b[i+1 : i+9])
+		fields[name] = string(b[i+9 : i+9+int(n)])
+		if b[i+9+int(n)] != '\n' {
+			t.Fatalf("binary field is not terminated: %q", b)
+		}
+		b = b[i+10+int(n):]
+	}
+
+	return fields
+}
+
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "socket")
+	return listenUnixgram(t, path), path
+}
+
+func readJournal(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+
+	buf, oob := make([]byte, 1<<16), make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oobn == 0 {
+		return parseJournal(t, buf[:n])
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := os.NewFile(uintptr(fds[0]), "journal")
+	defer f.Close()
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<30))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parseJournal(t, b)
+}
+
+func TestJournal(t *testing.T) {
+	conn, path := listenJournal(t)
+
+	j, err := NewJournal(JournalConfig{Address: path, Identifier: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	logger := New(nil)
+	logger.SetFlags(Lshortfile)
+	logger.AddSink(j.Sink(Debug))
+
+	logger.With(String("service", "api")).Warnw("multi\nline", "user.id", 1, "_hidden", true, Group("req", String("method", "GET")),
+		"priority", "high", "message", "user", "syslog_identifier", "other")
+	fields := readJournal(t, conn)
+
+	expected := map[string]string{
+		"MESSAGE":             "multi\nline",
+		"PRIORITY":            "4",
+		"SYSLOG_IDENTIFIER":   "app",
+		"CODE_FILE":           "journal_test.go",
+		"CODE_FUNC":           "github.com/pkgz/logg.TestJournal",
+		"SERVICE":             "api",
+		"USER_ID":             "1",
+		"HIDDEN":              "true",
+		"REQ_METHOD":          "GET",
+		"F_PRIORITY":          "high",
+		"F_MESSAGE":           "user",
+		"F_SYSLOG_IDENTIFIER": "other",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("wrong %s field. Expected: %q, received: %q", name, value, fields[name])
+		}
+	}
+	if fields["CODE_LINE"] == "" {
+		t.Error("CODE_LINE field is missing")
+	}
+}
+
+func TestJournal_empty(t *testing.T) {
+	conn, path := listenJournal(t)
+
+	j, err := NewJournal(JournalConfig{Address: path, Identifier: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	logger := New(nil)
+	logger.AddSink(j.Sink(Info))
+	logger.Warn("")
+
+	fields := readJournal(t, conn)
+	if v, ok := fields["MESSAGE"]; !ok || v != "" {
+		t.Errorf("empty MESSAGE field must be sent: %q", fields)
+	}
+	if fields["PRIORITY"] != "4" || fields["SYSLOG_IDENTIFIER"] != "app" {
+		t.Errorf("wrong fields of an empty message: %q", fields)
+	}
+}
+
+func TestJournal_file(t *testing.T) {
+	conn, path := listenJournal(t)
+
+	j, err := NewJournal(JournalConfig{Address: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	logger := New(nil)
+	logger.AddSink(j.Sink(Info))
+
+	msg := strings.Repeat("a", 1<<20)
+	logger.Info(msg)
+
+	fields := readJournal(t, conn)
+	if fields["MESSAGE"] != msg {
+		t.Errorf("wrong message length: %d", len(fields["MESSAGE"]))
+	}
+	if fields["PRIORITY"] != "6" {
+		t.Errorf("wrong priority: %s", fields["PRIORITY"])
+	}
+}
+
+func Test_journalName(t *testing.T) {
+	tests := map[string]string{
+		"id":         "ID",
+		"user-name":  "USER_NAME",
+		"_private":   "PRIVATE",
+		"1st":        "F_1ST",
+		"":           "F_",
+		"Ключ":       "F_",
+		"latency_ms": "LATENCY_MS",
+		"message":    "F_MESSAGE",
+		"priority":   "F_PRIORITY",
+		"Code_File":  "F_CODE_FILE",
+	}
+
+	for key, expected := range tests {
+		if name := journalName("", key); name != expected {
+			t.Errorf("wrong name of %q. Expected: %s, received: %s", key, expected, name)
+		}
+	}
+
+	if name := journalName("REQ", "id"); name != "REQ_ID" {
+		t.Errorf("wrong prefixed name: %s", name)
+	}
+	if name := journalName("SYSLOG", "identifier"); name != "F_SYSLOG_IDENTIFIER" {
+		t.Errorf("wrong prefixed reserved name: %s", name)
+	}
+	if name := journalName("", strings.Repeat("a", 100)); len(name) != 64 {
+		t.Errorf("name must be truncated to 64 characters: %d", len(name))
+	}
+}
@@ -0,0 +1,52 @@
+//go:build unix
+
+package logg
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// isMsgSize reports whether the datagram is too long.
+func isMsgSize(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// sendJournalFile writes the entry to an unlinked temporary file
+// and sends its descriptor to journald.
+func sendJournalFile(conn *net.UnixConn, b []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "logg-journal-")
+	if err != nil {
+		f, err = os.CreateTemp("", "logg-journal-")
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if err := write(f, b); err != nil {
+		return err
+	}
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	// WriteMsgUnix doesn't support connected datagram sockets
+	rights := syscall.UnixRights(int(f.Fd()))
+	werr := raw.Write(func(fd uintptr) bool {
+		err = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return err != syscall.EAGAIN
+	})
+	if werr != nil {
+		return werr
+	}
+
+	return err
+}
@@ -1,9 +1,17 @@
 package logg
 
 import (
+	stdjson "encoding/json"
+	"math"
+	"strconv"
 	"sync"
+	"time"
+	"unicode/utf8"
+	"unsafe"
 )
 
+const hex = "0123456789abcdef"
+
 type json struct {
 	buf []byte
 }
@@ -27,12 +35,7 @@ func newJson() *json {
 
 func (js *json) close() {
 	if len(js.buf) == 0 {
-		js.buf = append(js.buf, "{}"...)
-		return
-	}
-
-	if js.buf[len(js.buf)-1] != '"' && len(js.buf) != 1 {
-		js.buf = append(js.buf, '"')
+		js.buf = append(js.buf, '{')
 	}
 
 	js.buf = append(js.buf, '}')
@@ -47,21 +50,137 @@ func (js *json) put() {
 	jsonPool.Put(js)
 }
 
+// addField appends a separator (if needed) and a key to dst.
+// The value must be appended right after.
 func (js *json) addField(key string, dst []byte) []byte {
 	if key == "" {
 		return dst
 	}
 
-	if len(dst) > 3 {
-		dst = append(dst, '"')
+	if len(dst) != 0 && dst[len(dst)-1] != '{' {
 		dst = append(dst, ", "...)
 	}
 
-	dst = append(dst, '"')
-	dst = append(dst, key...)
-	dst = append(dst, '"')
+	dst = appendJSONString(dst, key)
 	dst = append(dst, ": "...)
-	dst = append(dst, '"')
 
 	return dst
 }
+
+// appendFields appends fields to the json object.
+func (js *json) appendFields(fields []Field) {
+	for _, f := range fields {
+		if f.Key == "" {
+			if f.typ == groupType {
+				js.appendFields(f.val.([]Field))
+			}
+			continue
+		}
+		js.buf = f.appendJSON(js.addField(f.Key, js.buf))
+	}
+}
+
+// appendJSON appends the field value as a json value to dst.
+func (f Field) appendJSON(dst []byte) []byte {
+	switch f.typ {
+	case stringType:
+		return appendJSONString(dst, f.str)
+	case intType:
+		return strconv.AppendInt(dst, int64(f.num), 10)
+	case uintType:
+		return strconv.AppendUint(dst, f.num, 10)
+	case floatType:
+		v := math.Float64frombits(f.num)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return appendJSONString(dst, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		return strconv.AppendFloat(dst, v, 'g', -1, 64)
+	case boolType:
+		return strconv.AppendBool(dst, f.num == 1)
+	case durationType:
+		return appendJSONString(dst, time.Duration(f.num).String())
+	case timeType:
+		dst = append(dst, '"')
+		dst = f.appendValue(dst)
+		return append(dst, '"')
+	case errorType:
+		if f.val == nil {
+			return append(dst, "null"...)
+		}
+		return appendJSONString(dst, f.val.(error).Error())
+	case groupType:
+		js := json{buf: append(dst, '{')}
+		js.appendFields(f.val.([]Field))
+		return append(js.buf, '}')
+	case anyType:
+		if f.val == nil {
+			return append(dst, "null"...)
+		}
+		b, err := stdjson.Marshal(f.val)
+		if err != nil {
+			s, _ := f.stringValue()
+			return appendJSONString(dst, s)
+		}
+		return append(dst, b...)
+	}
+
+	return append(dst, "null"...)
+}
+
+// appendJSONString appends s to dst as a quoted json string.
+// Special and control characters are escaped as described in RFC 8259,
+// invalid UTF-8 sequences are replaced with U+FFFD.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+
+	dst = append(dst, s[start:]...)
+
+	return append(dst, '"')
+}
+
+// appendJSONBytes is the same as appendJSONString, but for a byte slice.
+func appendJSONBytes(dst []byte, b []byte) []byte {
+	return appendJSONString(dst, *(*string)(unsafe.Pointer(&b)))
+}
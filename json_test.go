@@ -2,7 +2,11 @@ package logg
 
 import (
 	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestLogg_getJson(t *testing.T) {
@@ -31,8 +35,8 @@ func TestLogg_json_close(t *testing.T) {
 			expected: `{}`,
 		},
 		"some value": {
-			buf:      []byte(`"key": "value`),
-			expected: `"key": "value"}`,
+			buf:      []byte(`{"key": "value"`),
+			expected: `{"key": "value"}`,
 		},
 	}
 
@@ -70,21 +74,97 @@ func TestLogg_json_addField(t *testing.T) {
 		t.Error("field with empty key must be missed")
 	}
 
-	testString := `"1": "1`
-	js.buf = js.addField("1", []byte{})
+	testString := `{"1": 1`
+	js.buf = js.addField("1", []byte{'{'})
 	js.buf = append(js.buf, '1')
 	if string(js.buf) != testString {
 		t.Errorf("value in json is not valid. Expected: %s, received: %s.", testString, string(js.buf))
 	}
 
-	if js.buf[len(js.buf)-1] == '"' {
-		t.Error("last byte in buf cannot be closing double quote")
-	}
-
-	testString = `"1": "1", "2": "2`
+	testString = `{"1": 1, "2": 2`
 	js.buf = js.addField("2", js.buf)
 	js.buf = append(js.buf, '2')
 	if string(js.buf) != testString {
 		t.Errorf("value in json is not valid. Expected: %s, received: %s.", testString, string(js.buf))
 	}
 }
+
+func Test_appendJSONString(t *testing.T) {
+	tests := map[string]struct {
+		s        string
+		expected string
+	}{
+		"empty":        {s: "", expected: `""`},
+		"plain":        {s: "test", expected: `"test"`},
+		"quote":        {s: `a"b`, expected: `"a\"b"`},
+		"backslash":    {s: `a\b`, expected: `"a\\b"`},
+		"new line":     {s: "a\nb\r\t", expected: `"a\nb\r\t"`},
+		"control":      {s: "a\x01b\x1f", expected: `"a\u0001b\u001f"`},
+		"unicode":      {s: "привіт", expected: `"привіт"`},
+		"invalid utf8": {s: "a\xffb", expected: "\"a\ufffdb\""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := appendJSONString(nil, tc.s)
+			if string(buf) != tc.expected {
+				t.Errorf("wrong json string. Expected: %s, received: %s", tc.expected, string(buf))
+			}
+		})
+	}
+}
+
+func TestField_appendJSON(t *testing.T) {
+	tests := map[string]struct {
+		field    Field
+		expected string
+	}{
+		"string":    {field: String("k", "v\n"), expected: `"v\n"`},
+		"int":       {field: Int("k", -1), expected: `-1`},
+		"uint":      {field: Uint64("k", 1), expected: `1`},
+		"float":     {field: Float64("k", 1.5), expected: `1.5`},
+		"nan":       {field: Float64("k", math.NaN()), expected: `"NaN"`},
+		"bool":      {field: Bool("k", false), expected: `false`},
+		"duration":  {field: Duration("k", time.Second), expected: `"1s"`},
+		"time":      {field: Time("k", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), expected: `"2020-01-02T03:04:05Z"`},
+		"error":     {field: Err(errors.New(`"failed"`)), expected: `"\"failed\""`},
+		"nil error": {field: Err(nil), expected: `null`},
+		"nil":       {field: Any("k", nil), expected: `null`},
+		"any":       {field: Any("k", map[string]int{"a": 1}), expected: `{"a":1}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := tc.field.appendJSON(nil)
+			if string(buf) != tc.expected {
+				t.Errorf("wrong json value. Expected: %s, received: %s", tc.expected, string(buf))
+			}
+		})
+	}
+
+	buf := Any("k", make(chan int)).appendJSON(nil)
+	if len(buf) == 0 || buf[0] != '"' {
+		t.Errorf("value which cannot be marshaled must be a string, received: %s", string(buf))
+	}
+}
+
+func TestLogg_json_escaping(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = Lshortfile
+	logger.format = Json
+
+	logger.Infow("line \"one\"\nline two", "path", `C:\tmp`)
+
+	var out map[string]interface{}
+	if err := stdjson.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("output must be a valid json: %v (%s)", err, buf.String())
+	}
+
+	if out["message"] != "line \"one\"\nline two" || out["path"] != `C:\tmp` {
+		t.Errorf("wrong values after decoding: %v", out)
+	}
+	if _, ok := out["line"].(float64); !ok {
+		t.Errorf("line must be a number, received: %v", out["line"])
+	}
+}
@@ -0,0 +1,215 @@
+package logg
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelInfo describes a log level.
+type LevelInfo struct {
+	Level Level  // severity of the level
+	Short string // short label used in pretty output and for prefix detection: INF
+	Long  string // long label used for prefix detection: INFO
+	Color int    // color of the label in pretty output
+	Name  string // value of the level key in json output, Short if empty
+}
+
+type levelInfo struct {
+	level  Level
+	short  string
+	long   string
+	name   string
+	logfmt string
+	color  []byte
+}
+
+type levelTable struct {
+	list     []*levelInfo // ordered by severity
+	maxLabel int          // length of the longest label
+}
+
+var (
+	levelsMu sync.Mutex
+	levelsV  = newLevels() // *levelTable
+)
+
+func newLevels() *atomic.Value {
+	table := &levelTable{}
+	for _, l := range builtinLevels {
+		table = table.with(l)
+	}
+
+	v := &atomic.Value{}
+	v.Store(table)
+	return v
+}
+
+func loadLevels() *levelTable {
+	return levelsV.Load().(*levelTable)
+}
+
+// RegisterLevel adds a custom level. Level value and labels must be unique.
+// After registration the level can be used in MinLevel and it's detected
+// by prefix as built-in levels.
+func RegisterLevel(info LevelInfo) (Level, error) {
+	if info.Level == Empty {
+		return Empty, errors.New("logg: level value is reserved")
+	}
+	if info.Short == "" || info.Long == "" {
+		return Empty, errors.New("logg: level must have short and long labels")
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	table := loadLevels()
+	for _, l := range table.list {
+		if l.level == info.Level {
+			return Empty, errors.New("logg: level " + l.short + " has the same value")
+		}
+		if l.lookup(info.Short) || l.lookup(info.Long) {
+			return Empty, errors.New("logg: level " + l.short + " has the same label")
+		}
+	}
+
+	levelsV.Store(table.with(info))
+
+	return info.Level, nil
+}
+
+// with returns a copy of the table with a new level.
+func (t *levelTable) with(info LevelInfo) *levelTable {
+	l := &levelInfo{
+		level:  info.Level,
+		short:  info.Short,
+		long:   info.Long,
+		name:   info.Name,
+		logfmt: strings.ToLower(info.Long),
+		color:  generate(info.Color),
+	}
+	if l.name == "" {
+		l.name = l.short
+	}
+
+	table := &levelTable{
+		list:     make([]*levelInfo, 0, len(t.list)+1),
+		maxLabel: t.maxLabel,
+	}
+
+	i := 0
+	for i < len(t.list) && t.list[i].level < l.level {
+		i++
+	}
+	table.list = append(table.list, t.list[:i]...)
+	table.list = append(table.list, l)
+	table.list = append(table.list, t.list[i:]...)
+
+	for _, label := range []string{l.short, l.long} {
+		if len(label) > table.maxLabel {
+			table.maxLabel = len(label)
+		}
+	}
+
+	return table
+}
+
+// lookup returns a level with the short or long label.
+func (t *levelTable) lookup(label string) Level {
+	for _, l := range t.list {
+		if l.lookup(label) {
+			return l.level
+		}
+	}
+
+	return Empty
+}
+
+func (l *levelInfo) lookup(label string) bool {
+	return l.short == label || l.long == label
+}
+
+// info returns the level description.
+// Unknown levels are represented by the closest less severe level.
+func (l Level) info() *levelInfo {
+	list := loadLevels().list
+
+	i := 0
+	for i < len(list)-1 && list[i+1].level <= l {
+		i++
+	}
+
+	return list[i]
+}
+
+// String returns a short label of the level.
+func (l Level) String() string {
+	if l == Empty {
+		return ""
+	}
+
+	return l.info().short
+}
+
+// ParseLevel returns a level with the short or long label or the json name
+// (case-insensitive), or a level with the numeric value.
+func ParseLevel(s string) (Level, error) {
+	label := strings.TrimSpace(s)
+	for _, l := range loadLevels().list {
+		if strings.EqualFold(l.short, label) || strings.EqualFold(l.long, label) || strings.EqualFold(l.name, label) {
+			return l.level, nil
+		}
+	}
+
+	if v, err := strconv.Atoi(label); err == nil {
+		return Level(v), nil
+	}
+
+	return Empty, errors.New("logg: unknown level " + strconv.Quote(s))
+}
+
+// MarshalText returns a short label of the level.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText parses the level with ParseLevel.
+func (l *Level) UnmarshalText(b []byte) error {
+	level, err := ParseLevel(string(b))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+	return nil
+}
+
+// Legacy returns the level value used before levels were ordered by severity
+// (Debug = 0, Info = 1, Error = 2, Warning = 3, Panic = 4).
+// Custom levels are represented by the closest less severe built-in level.
+func (l Level) Legacy() int {
+	for v, lvl := range legacyLevels {
+		if lvl == l {
+			return v
+		}
+	}
+
+	i := 0
+	for i < len(builtinLevels)-1 && builtinLevels[i+1].Level <= l {
+		i++
+	}
+
+	return builtinLevels[i].Level.Legacy()
+}
+
+// LegacyLevel converts a level value used before levels were ordered
+// by severity (Debug = 0, Info = 1, Error = 2, Warning = 3, Panic = 4).
+func LegacyLevel(v int) Level {
+	if lvl, ok := legacyLevels[v]; ok {
+		return lvl
+	}
+
+	return Empty
+}
@@ -0,0 +1,126 @@
+package logg
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"testing"
+)
+
+// levels are registered once per test binary
+var (
+	notice, noticeErr = RegisterLevel(LevelInfo{Level: 2, Short: "NTC", Long: "NOTICE", Color: Blue})
+	audit, auditErr   = RegisterLevel(LevelInfo{Level: 10, Short: "AUD", Long: "AUDIT", Color: Magenta, Name: "audit"})
+)
+
+func TestRegisterLevel(t *testing.T) {
+	if noticeErr != nil || auditErr != nil {
+		t.Fatalf("levels must be registered: %v, %v", noticeErr, auditErr)
+	}
+
+	errTests := map[string]LevelInfo{
+		"same value":       {Level: Info, Short: "ABC", Long: "ABCD"},
+		"same short label": {Level: 3, Short: "NTC", Long: "ABCD"},
+		"same long label":  {Level: 3, Short: "ABC", Long: "ERROR"},
+		"empty label":      {Level: 3, Short: "ABC"},
+		"reserved value":   {Level: Empty, Short: "ABC", Long: "ABCD"},
+	}
+	for name, info := range errTests {
+		if _, err := RegisterLevel(info); err == nil {
+			t.Errorf("%s: level must not be registered", name)
+		}
+	}
+
+	if notice.String() != "NTC" || audit.String() != "AUD" {
+		t.Errorf("wrong level labels: %s, %s", notice, audit)
+	}
+
+	tests := map[string]struct {
+		input  string
+		format Format
+		color  bool
+		output string
+	}{
+		"short": {
+			input:  "NTC test",
+			output: "NTC test",
+		},
+		"long with brackets": {
+			input:  "[NOTICE] test",
+			output: "NTC test",
+		},
+		"filtered": {
+			input: "INF test",
+		},
+		"color": {
+			input:  "AUDIT test",
+			color:  true,
+			output: fmt.Sprintf("%s%s[1mAUD%s%s test", generate(Magenta), escape, escapeClose, escapeClose),
+		},
+		"json": {
+			input:  "AUDIT test",
+			format: Json,
+			output: `{"level": "audit", "message": "test"}`,
+		},
+		"logfmt": {
+			input:  "NOTICE test",
+			format: Logfmt,
+			output: "level=notice msg=test",
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.MinLevel(notice)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger.format = tc.format
+			logger.color = tc.color
+
+			_, _ = logger.Write([]byte(tc.input))
+
+			if output := readFromBuffer(buf); output != tc.output {
+				t.Errorf("wrong output. Expected: %s, received: %s", tc.output, output)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"TRC":    Trace,
+		"debug":  Debug,
+		"Info":   Info,
+		" warn ": Warning,
+		"ERR":    Error,
+		"fatal":  Fatal,
+		"notice": notice,
+		"-4":     Debug,
+		"6":      Level(6),
+	}
+
+	for s, expected := range tests {
+		level, err := ParseLevel(s)
+		if err != nil || level != expected {
+			t.Errorf("wrong level of %q. Expected: %d, received: %d (%v)", s, expected, level, err)
+		}
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestLevel_MarshalText(t *testing.T) {
+	b, err := stdjson.Marshal(map[string]Level{"level": Warning})
+	if err != nil || string(b) != `{"level":"WRN"}` {
+		t.Errorf("wrong json: %s (%v)", b, err)
+	}
+
+	var v struct{ Level Level }
+	if err := stdjson.Unmarshal([]byte(`{"Level": "error"}`), &v); err != nil || v.Level != Error {
+		t.Errorf("wrong level: %s (%v)", v.Level, err)
+	}
+}
@@ -23,46 +23,122 @@ Usage
 package logg
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"reflect"
+	"sync"
+	"time"
 )
 
 // A Logg represents an active logging object that generates lines of
 // output to an io.Writer. Each logging operation makes a single call to
 // the Logg's Write method.
+// A Logg can be used simultaneously from multiple goroutines. Writes of
+// the logger and its children (see With) to a writer are serialized.
 type Logg struct {
-	format format // output format (string/json)
+	*settings // shared with children created by With
+
+	bound *bound // fields attached with With
+}
+
+// settings of a logger and its children.
+type settings struct {
+	mu sync.RWMutex // guards settings below
+
+	format Format // output format (pretty/json/custom)
 	flags  int    // time format flags
 	color  bool   // colorize output
 
-	minLevel level
-	out      io.Writer
+	minLevel Level
+	out      *syncWriter
+	routes   []route // writers for levels, ordered by level
+	sinks    []sink  // additional outputs
+
+	exit  func(code int)   // called by Fatal, os.Exit by default
+	panic func(msg string) // called by Panic before panicking, nil by default
+
+	clock func() time.Time // time of messages, time.Now if nil
+	frame *frame           // caller of all messages, set by Freeze
 }
 
-// Create new a new logg.
-func New(w io.Writer) *Logg {
+// frame is a fixed caller of messages.
+type frame struct {
+	file string
+	line int
+}
+
+// route defines a writer for messages with the level and above.
+type route struct {
+	level Level
+	out   *syncWriter
+}
+
+// syncWriter serializes writes to the underlying writer,
+// so each message is written as one unit.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newSyncWriter(w io.Writer) *syncWriter {
 	if w == nil {
 		w = ioutil.Discard
 	}
 
-	return &Logg{
-		out: w,
+	return &syncWriter{w: w}
+}
+
+func (w *syncWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(b)
+}
+
+// flush flushes the underlying writer.
+func (w *syncWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_ = flush(w.w)
+}
+
+// sameWriter reports whether both writers are the same comparable value.
+func sameWriter(a, b io.Writer) bool {
+	t := reflect.TypeOf(a)
+	return t != nil && t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// Create new a new logg.
+func New(w io.Writer) *Logg {
+	return &Logg{settings: &settings{
+		out: newSyncWriter(w),
 
 		format:   DefaultFormat,
 		flags:    DefaultFlags,
 		color:    DefaultColorOutput,
 		minLevel: DefaultMinimumLevel,
-	}
+		exit:     os.Exit,
+	}}
+}
+
+// With returns a child logger which writes provided fields with each message.
+// The child logger shares settings with the parent logger: changes of
+// the format, level, writers or sinks of one of them apply to both.
+func (l *Logg) With(fields ...Field) *Logg {
+	return &Logg{settings: l.settings, bound: l.bound.with(fields)}
 }
 
 // Allows to create a new global logger.
 func NewGlobal(w io.Writer) {
-	logg = New(w)
+	l := New(w)
+	logg.Store(l)
 
-	log.SetOutput(logg)
+	log.SetOutput(l)
 	log.SetFlags(0)
 }
 
@@ -80,7 +156,12 @@ func (l *Logg) Write(b []byte) (n int, err error) {
 	return
 }
 
-func (l *Logg) write(calldepth int, level level, b []byte) {
+func (l *Logg) write(calldepth int, level Level, b []byte, kv ...interface{}) {
+	l.writeContext(nil, calldepth+1, level, b, kv)
+}
+
+// writeContext writes the message with fields of the context. ctx may be nil.
+func (l *Logg) writeContext(ctx context.Context, calldepth int, level Level, b []byte, kv []interface{}) {
 	if b == nil {
 		return
 	}
@@ -90,24 +171,156 @@ func (l *Logg) write(calldepth int, level level, b []byte) {
 		b = removeLevel(b, level)
 	}
 
-	if level != Empty && level < l.minLevel {
+	m := l.newMessage(level, ContextCallDepth+calldepth)
+	if m == nil {
 		return
 	}
 
-	m := newMessage(level, ContextCallDepth+calldepth, l.flags, l.format, l.color)
+	if ctx != nil {
+		m.fields = appendContextFields(m.fields, ctx)
+	}
+	m.fields = appendKeyValues(m.fields, kv)
+
+	m.write(m.build(b))
+	m.put()
+}
+
+// newMessage returns a message with logger settings and outputs for the level.
+// Returns nil if no output writes messages with the level.
+func (l *Logg) newMessage(level Level, calldepth int) *message {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 
-	out := l.out
-	if out == os.Stdout && (level > Error) {
-		out = os.Stderr
+	if level != Empty && !l.enabledLocked(level) {
+		return nil
 	}
 
-	if err := write(l.out, m.build(b)); err != nil {
+	m := newMessage(level, calldepth, l.flags, l.format, l.color)
+	m.clock, m.frame = l.clock, l.frame
+	m.withBound(l.bound)
+
+	if level == Empty || level >= l.minLevel {
+		m.withOutput(l.writerFor(level), l.format, nil, l.color)
+	}
+	for _, s := range l.sinks {
+		if level == Empty || level >= s.minLevel {
+			m.withOutput(s.out, s.format, s.formatter, s.color)
+		}
+	}
+
+	return m
+}
+
+// enabled reports whether messages with the level are written.
+func (l *Logg) enabled(level Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.enabledLocked(level)
+}
+
+// enabledLocked reports whether the logger or a sink writes messages
+// with the level. Must be called under the lock.
+func (l *Logg) enabledLocked(level Level) bool {
+	if level >= l.minLevel {
+		return true
+	}
+	for _, s := range l.sinks {
+		if level >= s.minLevel {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeMessage(w io.Writer, b []byte) {
+	if err := write(w, b); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "logg: could not write message: %v\n", err)
 	}
-	m.put()
+}
+
+// writerFor returns a writer for the level. Must be called under the lock.
+func (l *Logg) writerFor(level Level) *syncWriter {
+	if level == Empty {
+		return l.out
+	}
+
+	for i := len(l.routes) - 1; i >= 0; i-- {
+		if level >= l.routes[i].level {
+			return l.routes[i].out
+		}
+	}
+
+	return l.out
+}
+
+// syncWriter returns a writer which is already used by the logger for w
+// or a new one. Must be called under the lock.
+func (l *Logg) syncWriter(w io.Writer) *syncWriter {
+	if l.out != nil && sameWriter(l.out.w, w) {
+		return l.out
+	}
+	for _, r := range l.routes {
+		if sameWriter(r.out.w, w) {
+			return r.out
+		}
+	}
+	for _, s := range l.sinks {
+		if sameWriter(s.out.w, w) {
+			return s.out
+		}
+	}
+
+	return newSyncWriter(w)
+}
+
+// fatal flushes writers and terminates the program.
+func (l *Logg) fatal() {
+	l.mu.RLock()
+	out, routes, sinks, exit := l.out, l.routes, l.sinks, l.exit
+	l.mu.RUnlock()
+
+	out.flush()
+	for _, r := range routes {
+		r.out.flush()
+	}
+	for _, s := range sinks {
+		s.out.flush()
+	}
+	exit(1)
+}
+
+// now returns the current time of the logger clock.
+func (l *Logg) now() time.Time {
+	l.mu.RLock()
+	clock := l.clock
+	l.mu.RUnlock()
+
+	if clock == nil {
+		return time.Now()
+	}
+
+	return clock()
+}
+
+// panicMessage panics with the message. The panic function of
+// the logger is called before, it may stop the goroutine.
+func (l *Logg) panicMessage(msg string) {
+	l.mu.RLock()
+	fn := l.panic
+	l.mu.RUnlock()
+
+	if fn != nil {
+		fn(msg)
+	}
+	panic(msg)
 }
 
 // Writer returns the output destination for the standard logger.
 func (l *Logg) Writer() io.Writer {
-	return l.out
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.out.w
 }
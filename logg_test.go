@@ -7,6 +7,8 @@ import (
 	"log"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -14,7 +16,7 @@ import (
 func TestNew(t *testing.T) {
 	logger := New(nil)
 
-	if logger.out != ioutil.Discard {
+	if logger.Writer() != ioutil.Discard {
 		t.Error("logger writer must be ioutil.Discard if nil writer provided")
 	}
 
@@ -60,7 +62,7 @@ func TestNewGlobal(t *testing.T) {
 		t.Errorf("After setting global logg Log.Flags must be 0, received: %d", log.Flags())
 	}
 
-	if reflect.TypeOf(log.Writer()) != reflect.TypeOf(logg) {
+	if reflect.TypeOf(log.Writer()) != reflect.TypeOf(logg.Load()) {
 		t.Errorf("After setting global logg Log.Writer must be logg.Logg, received: %d", reflect.TypeOf(log.Writer()))
 	}
 }
@@ -115,7 +117,7 @@ func TestLogg(t *testing.T) {
 	tests := map[string]struct {
 		input string
 
-		format format
+		format Format
 		flags  int
 		color  bool
 
@@ -153,9 +155,9 @@ func TestLogg(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			logg.format = tc.format
-			logg.flags = tc.flags
-			logg.color = tc.color
+			logg.Load().format = tc.format
+			logg.Load().flags = tc.flags
+			logg.Load().color = tc.color
 
 			log.Print(tc.input)
 
@@ -164,9 +166,9 @@ func TestLogg(t *testing.T) {
 				t.Errorf("print error. Expected %s, received: %s", tc.output, output)
 			}
 
-			logg.format = DefaultFormat
-			logg.flags = DefaultFlags
-			logg.color = DefaultColorOutput
+			logg.Load().format = DefaultFormat
+			logg.Load().flags = DefaultFlags
+			logg.Load().color = DefaultColorOutput
 		})
 	}
 }
@@ -205,3 +207,371 @@ func readFromBuffer(buf *bytes.Buffer) string {
 	readBuf, _ := ioutil.ReadAll(buf)
 	return strings.Replace(string(readBuf), "\n", "", 1)
 }
+
+func TestLogg_With(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.color = false
+
+	child := logger.With(String("service", "api"))
+	child = child.With(Int("id", 1))
+
+	child.Infow("test", "user", "bob")
+	expected := "INF test service=api id=1 user=bob"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong pretty output. Expected: %s, received: %s", expected, output)
+	}
+
+	child.format = Json
+	child.Info("test")
+	expected = `{"level": "INF", "message": "test", "service": "api", "id": 1}`
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong json output. Expected: %s, received: %s", expected, output)
+	}
+
+	logger.Info("test")
+	expected = `{"level": "INF", "message": "test"}`
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("parent logger must not have child fields. Expected: %s, received: %s", expected, output)
+	}
+
+	if child.Writer() != logger.Writer() || child.minLevel != logger.minLevel || child.flags != logger.flags {
+		t.Error("child logger must have the same settings as the parent logger")
+	}
+}
+
+func TestLogg_With_sharedSettings(t *testing.T) {
+	logger := New(nil)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+	child := logger.With(String("service", "api"))
+
+	buf := new(bytes.Buffer)
+	logger.MinLevel(Debug)
+	logger.SetWriter(buf)
+
+	child.Debug("test")
+	expected := "DBG test service=api"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("child logger must follow the parent settings. Expected: %s, received: %s", expected, output)
+	}
+
+	child.MinLevel(Info)
+	logger.Debug("test")
+	if output := readFromBuffer(buf); output != "" {
+		t.Errorf("parent logger must follow the child settings, received: %s", output)
+	}
+}
+
+func TestLogg_Trace(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.color = false
+
+	logger.Trace("test")
+	if buf.Len() != 0 {
+		t.Errorf("trace message must be missed. Received: %s", readFromBuffer(buf))
+	}
+
+	logger.MinLevel(Trace)
+	logger.Tracef("test %d", 1)
+	if output := readFromBuffer(buf); output != "TRC test 1" {
+		t.Errorf("wrong trace output. Expected: %s, received: %s", "TRC test 1", output)
+	}
+
+	logger.Debug("test")
+	if output := readFromBuffer(buf); output != "DBG test" {
+		t.Errorf("debug message must be written with trace level. Received: %s", output)
+	}
+}
+
+type flushBuffer struct {
+	bytes.Buffer
+	flushed bool
+}
+
+func (b *flushBuffer) Flush() error {
+	b.flushed = true
+	return nil
+}
+
+func TestLogg_Fatal(t *testing.T) {
+	buf := new(flushBuffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.color = false
+
+	code := -1
+	logger.SetExitFunc(func(c int) { code = c })
+
+	logger.Fatalf("test %d", 1)
+
+	if output := readFromBuffer(&buf.Buffer); output != "FTL test 1" {
+		t.Errorf("wrong fatal output. Expected: %s, received: %s", "FTL test 1", output)
+	}
+	if !buf.flushed {
+		t.Error("writer must be flushed before exit")
+	}
+	if code != 1 {
+		t.Errorf("exit code must be 1, received: %d", code)
+	}
+}
+
+func TestLogg_Panic(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.color = false
+
+	defer func() {
+		r := recover()
+		if r != "test 1" {
+			t.Errorf("wrong panic value. Expected: %s, received: %v", "test 1", r)
+		}
+		if output := readFromBuffer(buf); output != "PNC test 1" {
+			t.Errorf("message must be written before panic. Expected: %s, received: %s", "PNC test 1", output)
+		}
+	}()
+
+	logger.Panicf("test %d", 1)
+	t.Error("Panicf must panic")
+}
+
+func TestLogg_MinLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = 0
+	logger.color = false
+	logger.MinLevel(Warning)
+
+	logger.Info("test")
+	logger.Warn("test")
+	logger.Error("test")
+	_, _ = logger.Write([]byte("DBG test\n"))
+	_, _ = logger.Write([]byte("ERR test\n"))
+
+	expected := "WRN test\nERR test\nERR test\n"
+	if output := buf.String(); output != expected {
+		t.Errorf("wrong filtered output. Expected: %q, received: %q", expected, output)
+	}
+}
+
+func TestLegacyLevel(t *testing.T) {
+	tests := map[int]Level{-1: Empty, 0: Debug, 1: Info, 2: Error, 3: Warning, 4: Panic, 100: Empty}
+
+	for v, lvl := range tests {
+		if LegacyLevel(v) != lvl {
+			t.Errorf("wrong level for legacy value %d. Expected: %d, received: %d", v, lvl, LegacyLevel(v))
+		}
+		if lvl != Empty && lvl.Legacy() != v {
+			t.Errorf("wrong legacy value for level %s. Expected: %d, received: %d", lvl, v, lvl.Legacy())
+		}
+	}
+
+	if Level(1).Legacy() != Info.Legacy() {
+		t.Errorf("unknown level must be represented by the closest less severe level, received: %d", Level(1).Legacy())
+	}
+
+	order := []Level{Trace, Debug, Info, Warning, Error, Panic, Fatal}
+	for i := 1; i < len(order); i++ {
+		if order[i-1] >= order[i] {
+			t.Errorf("level %s must be less severe than %s", order[i-1], order[i])
+		}
+	}
+}
+
+// unsafeWriter fails if Write is called concurrently.
+type unsafeWriter struct {
+	busy  int32
+	lines int32
+	err   error
+}
+
+func (w *unsafeWriter) Write(b []byte) (int, error) {
+	if !atomic.CompareAndSwapInt32(&w.busy, 0, 1) {
+		w.err = fmt.Errorf("concurrent write")
+		return len(b), nil
+	}
+	defer atomic.StoreInt32(&w.busy, 0)
+
+	if len(b) == 0 || b[len(b)-1] != '\n' {
+		w.err = fmt.Errorf("message is not written as one unit: %q", b)
+	}
+	atomic.AddInt32(&w.lines, 1)
+
+	return len(b), nil
+}
+
+func TestLogg_Concurrent(t *testing.T) {
+	w := &unsafeWriter{}
+	logger := New(w)
+	logger.MinLevel(Trace)
+	child := logger.With(String("service", "api"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				logger.Infow("test", "goroutine", i, "n", n)
+				child.Warnf("test %d", n)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < 20; n++ {
+				logger.SetFormat(Format(n % 3))
+				logger.SetFlags(LstdFlags | Lshortfile)
+				logger.ToggleColor(n%2 == 0)
+				logger.MinLevel(Trace)
+				logger.SetWriter(w)
+				_ = logger.Writer()
+				_ = logger.With(Int("n", n))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if w.err != nil {
+		t.Error(w.err)
+	}
+	if w.lines != 20*100*2 {
+		t.Errorf("all messages must be written. Expected: %d, received: %d", 20*100*2, w.lines)
+	}
+}
+
+func TestNewGlobal_Concurrent(t *testing.T) {
+	prev, prevOut, prevFlags := logg.Load(), log.Writer(), log.Flags()
+	defer func() {
+		logg.Store(prev)
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				NewGlobal(ioutil.Discard)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				Print("test")
+				_ = FromContext(nil)
+				_, _ = Logger(GlobalName)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLogg_SetLevelWriter(t *testing.T) {
+	out, errOut, panicOut := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	logger := New(out)
+	logger.flags = 0
+	logger.color = false
+	logger.SetLevelWriter(Panic, panicOut)
+	logger.SetLevelWriter(Error, errOut)
+
+	child := logger.With()
+
+	logger.Info("info")
+	logger.Warn("warn")
+	child.Error("error")
+	func() {
+		defer func() { _ = recover() }()
+		logger.Panic("panic")
+	}()
+	logger.Print("message")
+	_, _ = logger.Write([]byte("ERR standard log\n"))
+
+	if output := out.String(); output != "INF info\nWRN warn\nmessage\n" {
+		t.Errorf("wrong main writer output: %q", output)
+	}
+	if output := errOut.String(); output != "ERR error\nERR standard log\n" {
+		t.Errorf("wrong error writer output: %q", output)
+	}
+	if output := panicOut.String(); output != "PNC panic\n" {
+		t.Errorf("wrong panic writer output: %q", output)
+	}
+
+	out.Reset()
+	logger.SetLevelWriter(Error, nil)
+	logger.Error("error")
+	if output := readFromBuffer(out); output != "ERR error" {
+		t.Errorf("error must be written to the main writer after route removal: %q", output)
+	}
+}
+
+func TestLogg_Freeze(t *testing.T) {
+	tests := map[string]struct {
+		format Format
+		output string
+	}{
+		"pretty": {
+			format: Pretty,
+			output: "2020-01-02 03:04:05.000006 service/api.go:10 INF request method=GET status=200\n",
+		},
+		"json": {
+			format: Json,
+			output: `{"time": "2020-01-02T03:04:05.000006Z", "file": "service/api.go", "line": 10, "level": "INF", "message": "request", "method": "GET", "status": 200}` + "\n",
+		},
+		"logfmt": {
+			format: Logfmt,
+			output: "time=2020-01-02T03:04:05.000006Z level=info caller=service/api.go:10 msg=request method=GET status=200\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			l := New(buf)
+			l.SetFormat(tc.format)
+			l.SetFlags(Ldate | Ltime | Lmicroseconds | Llongfile | LUTC)
+			l.ToggleColor(false)
+			l.Freeze(time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC), "service/api.go", 10)
+
+			l.With(String("method", "GET")).Infow("request", "status", 200)
+
+			if buf.String() != tc.output {
+				t.Errorf("wrong output.\nExpected: %q\nReceived: %q", tc.output, buf.String())
+			}
+		})
+	}
+}
+
+func TestLogg_SetClock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf)
+	l.SetFlags(Ltime | Lshortfile)
+	l.ToggleColor(false)
+
+	l.Freeze(time.Time{}, "service/api.go", 10)
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	l.SetClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+
+	l.Info("first")
+	l.Info("second")
+
+	expected := "03:04:06 api.go:10 INF first\n03:04:07 api.go:10 INF second\n"
+	if buf.String() != expected {
+		t.Errorf("wrong output.\nExpected: %q\nReceived: %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	l.Freeze(time.Time{}, "", 0)
+	l.Info("message")
+	if !strings.Contains(buf.String(), "logg_test.go:") {
+		t.Errorf("caller must be restored: %q", buf.String())
+	}
+}
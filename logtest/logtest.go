@@ -0,0 +1,172 @@
+// Package logtest provides a logger which records entries for tests.
+//
+//	log, rec := logtest.New()
+//	service := NewService(log)
+//	service.Run()
+//	rec.AssertLogged(t, logg.Error, "connection refused")
+package logtest
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkgz/logg"
+)
+
+// Entry is a recorded log entry.
+type Entry struct {
+	Time    time.Time
+	Level   logg.Level
+	Message string
+	Fields  []logg.Field // fields attached with With followed by message fields
+	File    string       // full path of the caller file
+	Line    int
+}
+
+// Field returns the value of the field with the key. Fields of groups
+// are found by dotted keys (req.method).
+func (e Entry) Field(key string) (interface{}, bool) {
+	return findField(e.Fields, key)
+}
+
+func findField(fields []logg.Field, key string) (interface{}, bool) {
+	for _, f := range fields {
+		if f.Key == key {
+			return f.Value(), true
+		}
+
+		group, ok := f.Value().([]logg.Field)
+		switch {
+		case !ok:
+		case f.Key == "":
+			if v, ok := findField(group, key); ok {
+				return v, true
+			}
+		case strings.HasPrefix(key, f.Key+"."):
+			if v, ok := findField(group, key[len(f.Key)+1:]); ok {
+				return v, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Recorder is a logg.Formatter which records entries instead of formatting
+// them. Empty messages are recorded too.
+type Recorder struct {
+	mu       sync.Mutex
+	entries  []Entry
+	exitCode int
+	exited   bool
+}
+
+// New returns a logger which records entries of all levels in a sink,
+// so SetFormat and MinLevel of the logger don't change recording. Fatal
+// messages don't exit, use Exited to check whether Fatal was called.
+func New() (*logg.Logg, *Recorder) {
+	r := &Recorder{}
+
+	l := logg.New(io.Discard)
+	l.AddSink(logg.Sink{Writer: io.Discard, Formatter: r, MinLevel: logg.Trace})
+	l.SetFlags(logg.Ldate | logg.Ltime | logg.Lmicroseconds | logg.Llongfile)
+	l.ToggleColor(false)
+	l.SetExitFunc(r.exit)
+
+	return l, r
+}
+
+// Format records the entry. Nothing is written to dst.
+func (r *Recorder) Format(dst []byte, e *logg.Entry) []byte {
+	entry := Entry{
+		Time:    e.Time,
+		Level:   e.Level,
+		Message: string(e.Message),
+		File:    e.File,
+		Line:    e.Line,
+	}
+	if len(e.Fields) != 0 {
+		entry.Fields = append([]logg.Field(nil), e.Fields...)
+	}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+
+	return dst
+}
+
+// FormatsEmpty marks the recorder as a logg.EmptyFormatter.
+func (r *Recorder) FormatsEmpty() {}
+
+func (r *Recorder) exit(code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exitCode, r.exited = code, true
+}
+
+// Entries returns recorded entries.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Entry(nil), r.entries...)
+}
+
+// FilterLevel returns recorded entries with the level.
+func (r *Recorder) FilterLevel(level logg.Level) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []Entry
+	for _, e := range r.entries {
+		if e.Level == level {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// Exited returns the exit code if Fatal was called.
+func (r *Recorder) Exited() (code int, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.exitCode, r.exited
+}
+
+// Reset removes recorded entries and the exit code.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+	r.exitCode, r.exited = 0, false
+}
+
+// AssertLogged fails the test if there is no entry with the level
+// and a message which contains substr.
+func (r *Recorder) AssertLogged(t testing.TB, level logg.Level, substr string) {
+	t.Helper()
+
+	entries := r.Entries()
+	for _, e := range entries {
+		if e.Level == level && strings.Contains(e.Message, substr) {
+			return
+		}
+	}
+
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString("\n\t")
+		b.WriteString(e.Level.String())
+		b.WriteByte(' ')
+		b.WriteString(e.Message)
+	}
+	t.Errorf("no %s entry with message %q, recorded entries:%s", level, substr, b.String())
+}
@@ -0,0 +1,114 @@
+package logtest
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/pkgz/logg"
+)
+
+func TestRecorder(t *testing.T) {
+	l, rec := New()
+
+	l.With(logg.String("service", "api")).Infow("started", "port", 8080)
+	_, file, line, _ := runtime.Caller(0)
+	l.Trace("trace")
+	l.Errorw("failed", logg.Err(errors.New("timeout")), logg.Group("req", logg.String("method", "GET")))
+
+	entries := rec.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, received: %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.Level != logg.Info || e.Message != "started" {
+		t.Errorf("wrong entry: %s %s", e.Level, e.Message)
+	}
+	if e.File != file || e.Line != line-1 {
+		t.Errorf("wrong caller. Expected: %s:%d, received: %s:%d", filepath.Base(file), line-1, filepath.Base(e.File), e.Line)
+	}
+	if e.Time.IsZero() {
+		t.Error("entry time must be set")
+	}
+	if v, ok := e.Field("service"); !ok || v != "api" {
+		t.Errorf("wrong service field: %v", v)
+	}
+	if v, ok := e.Field("port"); !ok || v != int64(8080) {
+		t.Errorf("wrong port field: %v", v)
+	}
+
+	e = entries[2]
+	if v, ok := e.Field("req.method"); !ok || v != "GET" {
+		t.Errorf("wrong group field: %v", v)
+	}
+	if v, ok := e.Field("error"); !ok || v.(error).Error() != "timeout" {
+		t.Errorf("wrong error field: %v", v)
+	}
+	if _, ok := e.Field("req.path"); ok {
+		t.Error("missing field must not be found")
+	}
+
+	if entries := rec.FilterLevel(logg.Trace); len(entries) != 1 || entries[0].Message != "trace" {
+		t.Errorf("wrong filtered entries: %v", entries)
+	}
+
+	rec.AssertLogged(t, logg.Error, "fail")
+
+	rec.Reset()
+	if entries := rec.Entries(); len(entries) != 0 {
+		t.Errorf("entries must be removed: %v", entries)
+	}
+}
+
+func TestRecorder_empty(t *testing.T) {
+	l, rec := New()
+	l.SetFormat(logg.Json)
+
+	l.Error("")
+	_, file, line, _ := runtime.Caller(0)
+	l.Info("test")
+
+	entries := rec.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, received: %d", len(entries))
+	}
+	if e := entries[0]; e.Level != logg.Error || e.Message != "" || e.File != file || e.Line != line-1 {
+		t.Errorf("wrong empty entry: %s %q %s:%d", e.Level, e.Message, filepath.Base(e.File), e.Line)
+	}
+	if e := entries[1]; e.Level != logg.Info || e.Message != "test" {
+		t.Errorf("wrong entry: %s %q", e.Level, e.Message)
+	}
+}
+
+func TestRecorder_Fatal(t *testing.T) {
+	l, rec := New()
+
+	l.Fatal("stop")
+
+	if code, ok := rec.Exited(); !ok || code != 1 {
+		t.Errorf("fatal exit must be recorded: %d, %v", code, ok)
+	}
+	rec.AssertLogged(t, logg.Fatal, "stop")
+}
+
+func TestRecorder_AssertLogged(t *testing.T) {
+	l, rec := New()
+	l.Info("started")
+
+	ft := &fakeT{}
+	rec.AssertLogged(ft, logg.Error, "started")
+	if !ft.failed {
+		t.Error("assertion must fail without an entry with the level")
+	}
+}
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) { t.failed = true }
@@ -1,19 +1,59 @@
 package logg
 
 import (
-	"strconv"
 	"sync"
 	"time"
 )
 
 type message struct {
-	level     level
+	level     Level
 	calldepth int
+	pc        uintptr // program counter of the caller, calldepth is used if 0
+	frame     *frame  // fixed caller, used instead of pc and calldepth
+	clock     func() time.Time
+	time      time.Time // time of the message, used if the clock is nil and it isn't zero
 	flags     int
-	format    format
+	format    Format
 	color     bool
 
-	buf []byte
+	fields []Field
+	bound  *bound
+	entry  Entry
+	built  bool     // entry is filled and formatted
+	record bool     // slog record, formatted without text and fields too
+	cur    int      // output which format is in buf
+	empty  bool     // entry of an empty message is filled for an EmptyFormatter
+	outs   []output // outputs for the message level
+	text   []byte   // copy of the message text, keeps the caller's buffer on the stack
+	buf    []byte
+}
+
+// output is a writer with format settings of the logger or a sink.
+type output struct {
+	w         *syncWriter
+	format    Format
+	formatter Formatter // formatter of a sink, used instead of format if set
+	color     bool
+	done      bool // message is written to the output
+}
+
+// getFormatter returns the formatter of the output.
+func (o *output) getFormatter() Formatter {
+	if o.formatter != nil {
+		return o.formatter
+	}
+
+	return o.format.Formatter()
+}
+
+// sameFormat reports whether both outputs write the same line. Outputs
+// with sink formatters are formatted separately.
+func (o *output) sameFormat(p *output) bool {
+	if o == p {
+		return true
+	}
+
+	return o.formatter == nil && p.formatter == nil && o.format == p.format && o.color == p.color
 }
 
 var messagePool = sync.Pool{
@@ -26,15 +66,26 @@ var messagePool = sync.Pool{
 }
 
 // fetch a message from sync.Pool.
-func newMessage(level level, calldepth int, flags int, format format, color bool) *message {
+func newMessage(level Level, calldepth int, flags int, format Format, color bool) *message {
 	m := messagePool.Get().(*message)
 
 	m.level = level
 	m.calldepth = calldepth
+	m.pc = 0
+	m.frame = nil
+	m.clock = nil
+	m.time = time.Time{}
 	m.flags = flags
 	m.format = format
 	m.color = color
 
+	m.fields = m.fields[:0]
+	m.bound = nil
+	m.built = false
+	m.record = false
+	m.cur = 0
+	m.empty = false
+	m.outs = m.outs[:0]
 	m.buf = m.buf[:0]
 
 	return m
@@ -43,98 +94,160 @@ func newMessage(level level, calldepth int, flags int, format format, color bool
 // reset message object and put to sync.Pool.
 func (m *message) put() {
 	const maxSize = 1 << 16 // 64KiB
-	if cap(m.buf) > maxSize {
+	if cap(m.buf) > maxSize || cap(m.text) > maxSize {
 		return
 	}
 
+	for i := range m.fields {
+		m.fields[i] = Field{}
+	}
+	m.fields = m.fields[:0]
+	for i := range m.outs {
+		m.outs[i] = output{}
+	}
+	m.outs = m.outs[:0]
+	m.bound = nil
+	m.frame = nil
+	m.clock = nil
+	m.time = time.Time{}
+	m.entry = Entry{}
+
 	messagePool.Put(m)
 }
 
-func (m *message) build(b []byte) []byte {
-	if len(b) != 0 {
-		if m.format == Json {
-			m.buildJSON(b)
-		} else {
-			m.buildPretty(b)
-		}
+// withBound adds fields attached to the logger before message fields.
+func (m *message) withBound(b *bound) {
+	if b == nil {
+		return
 	}
 
-	return append(m.buf, '\n')
+	m.bound = b
+	m.fields = append(m.fields, b.fields...)
 }
 
-func (m *message) buildJSON(b []byte) {
-	js := newJson()
-
-	if m.flags&(Ldate|Ltime|Lmicroseconds) != 0 {
-		js.buf = append(appendTimestamp(time.Now(), m.format, m.flags, js.addField("time", js.buf)))
+// withOutput adds an output. The first output defines the format of build.
+func (m *message) withOutput(w *syncWriter, format Format, formatter Formatter, color bool) {
+	if len(m.outs) == 0 {
+		m.format, m.color = format, color
 	}
 
-	if m.flags&(Lshortfile|Llongfile) != 0 {
-		file, line := caller(m.calldepth, m.flags&Lshortfile != 0)
+	m.outs = append(m.outs, output{w: w, format: format, formatter: formatter, color: color})
+}
 
-		js.buf = append(js.addField("file", js.buf), file...)
-		js.buf = append(js.addField("line", js.buf), strconv.Itoa(line)...)
+// formatter returns the formatter of build.
+func (m *message) formatter() Formatter {
+	if len(m.outs) != 0 {
+		return m.outs[0].getFormatter()
 	}
 
-	if m.level != Empty {
-		js.buf = append(js.addField("level", js.buf), levels[m.level]...)
-	}
+	return m.format.Formatter()
+}
 
-	if len(b) != 0 {
-		js.buf = append(js.addField("message", js.buf), b...)
+func (m *message) build(b []byte) []byte {
+	switch {
+	case len(b) != 0 || len(m.fields) > m.bound.len() || m.record:
+		m.buf = m.formatter().Format(m.buf, m.makeEntry(b))
+		m.built = true
+	case m.formatsEmpty():
+		m.makeEntry(b)
+		m.empty = true
 	}
 
-	js.close()
-	m.buf = js.buf
-
-	js.put()
+	return m.line()
 }
 
-func (m *message) buildPretty(b []byte) {
-	if m.flags&(Ldate|Ltime|Lmicroseconds) != 0 {
-		if m.color {
-			m.buf = append(m.buf, timeColor...)
-			m.buf = appendTimestamp(time.Now(), m.format, m.flags, m.buf)
-			m.buf = append(m.buf, []byte(escapeClose)...)
-		} else {
-			m.buf = appendTimestamp(time.Now(), m.format, m.flags, m.buf)
+// formatsEmpty reports whether an output formats empty messages.
+func (m *message) formatsEmpty() bool {
+	for i := range m.outs {
+		if _, ok := m.outs[i].getFormatter().(EmptyFormatter); ok {
+			return true
 		}
 	}
 
-	if m.flags&(Lshortfile|Llongfile) != 0 {
-		file, line := caller(m.calldepth, m.flags&Lshortfile != 0)
+	return false
+}
 
-		if len(m.buf) != 0 && m.buf[len(m.buf)-1] != ' ' {
-			m.buf = append(m.buf, ' ')
-		}
+// line appends a new line to the formatted message.
+func (m *message) line() []byte {
+	line := append(m.buf, '\n')
+	m.buf = line[:len(line)-1] // keep grown buffer for the next message
 
-		m.buf = append(m.buf, file+":"...)
-		m.buf = append(m.buf, strconv.Itoa(line)...)
-	}
+	return line
+}
 
-	if m.level != Empty {
-		if m.flags&(Ldate|Ltime|Lmicroseconds) != 0 || m.flags&(Lshortfile|Llongfile) != 0 {
-			m.buf = append(m.buf, ' ')
+// write writes the line returned by build to outputs. The entry is
+// formatted once for each distinct format and color of outputs.
+func (m *message) write(line []byte) {
+	for i := range m.outs {
+		o := &m.outs[i]
+		if o.done {
+			continue
 		}
 
-		if m.color && m.level != Empty {
-			m.buf = append(m.buf, colors[m.level]...)
-			m.buf = append(m.buf, escape+"[1m"...)
+		switch {
+		case m.built && !o.sameFormat(&m.outs[m.cur]):
+			m.cur = i
+			m.entry.Color = o.color
+			m.buf = o.getFormatter().Format(m.buf[:0], &m.entry)
+			line = m.line()
+		case m.empty:
+			m.buf = m.buf[:0]
+			if f, ok := o.getFormatter().(EmptyFormatter); ok {
+				m.entry.Color = o.color
+				m.buf = f.Format(m.buf, &m.entry)
+			}
+			line = m.line()
 		}
 
-		m.buf = append(m.buf, levels[m.level]...)
-
-		if m.color && m.level != Empty {
-			m.buf = append(m.buf, escapeClose...)
-			m.buf = append(m.buf, escapeClose...)
+		for j := i; j < len(m.outs); j++ {
+			if s := &m.outs[j]; !s.done && s.sameFormat(o) {
+				writeMessage(s.w, line)
+				s.done = true
+			}
 		}
 	}
+}
+
+// makeEntry fills the message entry. Must be called directly from build,
+// caller depth depends on it.
+func (m *message) makeEntry(b []byte) *Entry {
+	e := &m.entry
+
+	e.Level = m.level
+	m.text = append(m.text[:0], b...)
+	e.Message = m.text
+	e.Fields = m.fields
+	e.Flags = m.flags
+	e.Color = m.color
+	e.bound = m.bound
 
-	if len(b) != 0 {
-		if len(m.buf) != 0 && m.buf[len(m.buf)-1] != ' ' {
-			m.buf = append(m.buf, ' ')
+	e.Time = time.Time{}
+	if m.flags&(Ldate|Ltime|Lmicroseconds) != 0 {
+		switch {
+		case m.clock != nil:
+			e.Time = m.clock()
+		case !m.time.IsZero():
+			e.Time = m.time
+		default:
+			e.Time = time.Now()
 		}
+	}
 
-		m.buf = append(m.buf, b...)
+	e.PC, e.File, e.Line = 0, "", 0
+	if m.flags&(Lshortfile|Llongfile) != 0 {
+		switch {
+		case m.frame != nil:
+			e.File, e.Line = m.frame.file, m.frame.line
+			if m.flags&Lshortfile != 0 {
+				e.File = shortName(e.File)
+			}
+		case m.pc != 0:
+			e.PC = m.pc
+			e.File, e.Line = callerPC(m.pc, m.flags&Lshortfile != 0)
+		default:
+			e.PC, e.File, e.Line = caller(m.calldepth, m.flags&Lshortfile != 0)
+		}
 	}
+
+	return e
 }
@@ -10,10 +10,10 @@ import (
 
 func TestLogg_newMessage(t *testing.T) {
 	tests := map[string]struct {
-		level     level
+		level     Level
 		calldepth int
 		flags     int
-		format    format
+		format    Format
 		color     bool
 	}{
 		"empty": {},
@@ -87,7 +87,7 @@ func TestLogg_message_build(t *testing.T) {
 	tests := map[string]struct {
 		data []byte
 
-		level     level
+		level     Level
 		calldepth int
 		flags     int
 		color     bool
@@ -117,7 +117,7 @@ func TestLogg_message_build(t *testing.T) {
 			flags:     Lshortfile,
 			calldepth: 3,
 			pretty:    []byte("$1:$2 test"),
-			json:      []byte(`{"file": "$1", "line": "$2", "message": "test"}`),
+			json:      []byte(`{"file": "$1", "line": $2, "message": "test"}`),
 		},
 		"level": {
 			data:   []byte("test"),
@@ -145,7 +145,7 @@ func TestLogg_message_build(t *testing.T) {
 			flags:     LstdFlags | Lshortfile,
 			calldepth: 3,
 			pretty:    []byte(fmt.Sprintf("%s $1:$2 WRN test", time.Now().Format("2006-01-02 15:04:05"))),
-			json:      []byte(fmt.Sprintf(`{"time": "%s", "file": "$1", "line": "$2", "level": "WRN", "message": "test"}`, time.Now().Format(time.RFC3339))),
+			json:      []byte(fmt.Sprintf(`{"time": "%s", "file": "$1", "line": $2, "level": "WRN", "message": "test"}`, time.Now().Format(time.RFC3339))),
 		},
 		"color + time + level + message": {
 			data:  []byte("test"),
@@ -174,7 +174,7 @@ func TestLogg_message_build(t *testing.T) {
 				escapeClose,
 				escapeClose,
 			)),
-			json: []byte(fmt.Sprintf(`{"time": "%s", "file": "$1", "line": "$2", "level": "WRN", "message": "test"}`, time.Now().Format(time.RFC3339))),
+			json: []byte(fmt.Sprintf(`{"time": "%s", "file": "$1", "line": $2, "level": "WRN", "message": "test"}`, time.Now().Format(time.RFC3339))),
 		},
 	}
 
@@ -184,7 +184,7 @@ func TestLogg_message_build(t *testing.T) {
 			m.build(tc.data)
 
 			if tc.flags&(Lshortfile|Llongfile) != 0 {
-				file, line := caller(1, tc.flags&Lshortfile != 0)
+				_, file, line := caller(1, tc.flags&Lshortfile != 0)
 				tc.pretty = []byte(strings.Replace(string(tc.pretty), "$1", file, 1))
 				tc.pretty = []byte(strings.Replace(string(tc.pretty), "$2", fmt.Sprint(line-3), 1))
 			}
@@ -197,7 +197,7 @@ func TestLogg_message_build(t *testing.T) {
 			m.build(tc.data)
 
 			if tc.flags&(Lshortfile|Llongfile) != 0 {
-				file, line := caller(1, tc.flags&Lshortfile != 0)
+				_, file, line := caller(1, tc.flags&Lshortfile != 0)
 				tc.json = []byte(strings.Replace(string(tc.json), "$1", file, 1))
 				tc.json = []byte(strings.Replace(string(tc.json), "$2", fmt.Sprint(line-3), 1))
 			}
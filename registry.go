@@ -0,0 +1,60 @@
+package logg
+
+import (
+	"sort"
+	"sync"
+)
+
+// GlobalName is a name of the global logger in the registry.
+const GlobalName = "global"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Logg{}
+)
+
+// RegisterLogger adds the logger to the registry, so its level can be
+// changed with LevelHandler and signals. A nil logger removes the name.
+// GlobalName is reserved for the global logger.
+func RegisterLogger(name string, l *Logg) {
+	if name == GlobalName {
+		return
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if l == nil {
+		delete(registry, name)
+		return
+	}
+	registry[name] = l
+}
+
+// Logger returns the registered logger or the global logger for GlobalName.
+func Logger(name string) (*Logg, bool) {
+	if name == GlobalName {
+		return logg.Load(), true
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	l, ok := registry[name]
+	return l, ok
+}
+
+// LoggerNames returns sorted names of registered loggers and GlobalName.
+func LoggerNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry)+1)
+	names = append(names, GlobalName)
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	return names
+}
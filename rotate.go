@@ -0,0 +1,385 @@
+package logg
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is a timestamp format in names of rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateConfig defines when a RotatingFile is rotated and
+// how many rotated files are kept.
+type RotateConfig struct {
+	MaxSize        int64         // maximum size of the file in bytes, 0 disables rotation by size
+	Interval       time.Duration // rotation interval (e.g. 24 * time.Hour), 0 disables rotation by time
+	MaxBackups     int           // maximum number of rotated files, 0 keeps all files
+	Compress       bool          // gzip rotated files
+	ReopenOnSignal bool          // reopen the file on SIGHUP (for external logrotate)
+}
+
+// RotatingFile is an io.Writer which writes to a file and rotates it
+// by size and/or by time. Rotated files are renamed to
+// name-2006-01-02T15-04-05.000.ext (name-....ext.gz if compressed)
+// in the same directory.
+//
+//	f, err := logg.NewRotatingFile("/var/log/app.log", logg.RotateConfig{MaxSize: 100 << 20, MaxBackups: 5})
+//	log.SetWriter(f)
+type RotatingFile struct {
+	path   string
+	cfg    RotateConfig
+	now    func() time.Time
+	rename func(oldpath, newpath string) error
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	mill    sync.Mutex     // serializes compression and cleanup of rotated files
+	wg      sync.WaitGroup // running compression and cleanup
+	stopSig func()
+}
+
+// NewRotatingFile opens or creates the file for appending.
+func NewRotatingFile(path string, cfg RotateConfig) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:   path,
+		cfg:    cfg,
+		now:    time.Now,
+		rename: os.Rename,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReopenOnSignal {
+		f.stopSig = notifyReopen(f)
+	}
+
+	return f, nil
+}
+
+// Write writes b to the file. The file is rotated before the write
+// if the write exceeds MaxSize or the rotation interval has passed.
+func (f *RotatingFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	if f.needRotate(int64(len(b))) {
+		if err := f.rotate(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logg: could not rotate %s: %v\n", f.path, err)
+		}
+	}
+
+	// the file could not be opened after the rotation
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(b)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Rotate closes the file, renames it and opens a new file.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	return f.rotate()
+}
+
+// Reopen closes and opens the file. Use it after the file was
+// moved by an external tool.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+
+	return f.open()
+}
+
+// Sync commits the file content to the storage.
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Sync()
+}
+
+// Close closes the file and waits for the compression of rotated files.
+// Next calls do nothing.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	stopSig := f.stopSig
+	f.stopSig = nil
+	f.mu.Unlock()
+
+	if stopSig != nil {
+		stopSig()
+	}
+
+	f.wg.Wait()
+
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	if f.size != 0 {
+		f.openedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+func (f *RotatingFile) needRotate(n int64) bool {
+	if f.cfg.MaxSize > 0 && f.size != 0 && f.size+n > f.cfg.MaxSize {
+		return true
+	}
+
+	if f.cfg.Interval > 0 && !f.now().Truncate(f.cfg.Interval).Equal(f.openedAt.Truncate(f.cfg.Interval)) {
+		return true
+	}
+
+	return false
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+
+	if f.size != 0 {
+		backup := f.backupName(f.now())
+		if err := f.rename(f.path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+			// keep writing to the file, the next rotation is tried
+			// after MaxSize bytes or the interval
+			if oerr := f.open(); oerr != nil {
+				return errors.Join(err, oerr)
+			}
+			f.size, f.openedAt = 0, f.now()
+			return err
+		}
+
+		f.wg.Add(1)
+		go f.millRotated(backup)
+	}
+
+	return f.open()
+}
+
+// backupName returns a free name for the rotated file.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	base := filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat))
+
+	name := base + ext
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+
+	return name
+}
+
+// nameParts returns the directory, the prefix of rotated files and the file extension.
+func (f *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	name := filepath.Base(f.path)
+	ext = filepath.Ext(name)
+
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// millRotated compresses the rotated file and removes old rotated files.
+func (f *RotatingFile) millRotated(name string) {
+	defer f.wg.Done()
+
+	f.mill.Lock()
+	defer f.mill.Unlock()
+
+	if f.cfg.Compress {
+		if err := compressFile(name); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logg: could not compress %s: %v\n", name, err)
+		}
+	}
+
+	if f.cfg.MaxBackups > 0 {
+		if err := f.removeBackups(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logg: could not remove rotated files: %v\n", err)
+		}
+	}
+}
+
+// backups returns rotated files ordered from the newest to the oldest.
+func (f *RotatingFile) backups() ([]string, error) {
+	dir, prefix, ext := f.nameParts()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		name string
+		t    time.Time
+	}
+	var list []backup
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		ts := strings.TrimPrefix(name, prefix)
+		ts = strings.TrimSuffix(ts, ".gz")
+		ts = strings.TrimSuffix(ts, ext)
+		if len(ts) < len(backupTimeFormat) {
+			continue
+		}
+
+		t, err := time.Parse(backupTimeFormat, ts[:len(backupTimeFormat)])
+		if err != nil {
+			continue
+		}
+		list = append(list, backup{name: filepath.Join(dir, name), t: t})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].t.Equal(list[j].t) {
+			return list[i].name > list[j].name
+		}
+		return list[i].t.After(list[j].t)
+	})
+
+	names := make([]string, len(list))
+	for i, b := range list {
+		names[i] = b.name
+	}
+
+	return names, nil
+}
+
+func (f *RotatingFile) removeBackups() error {
+	names, err := f.backups()
+	if err != nil || len(names) <= f.cfg.MaxBackups {
+		return err
+	}
+
+	for _, name := range names[f.cfg.MaxBackups:] {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compressFile compresses the file to name.gz and removes the file.
+func compressFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(name + ".gz")
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(name)
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package logg
+
+// notifyReopen does nothing, SIGHUP is not supported on this platform.
+func notifyReopen(f *RotatingFile) func() {
+	return nil
+}
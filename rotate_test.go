@@ -0,0 +1,240 @@
+package logg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_size(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	for _, s := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		now = now.Add(time.Second)
+		if _, err := f.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, _ := os.ReadFile(path); string(b) != "line4\n" {
+		t.Errorf("wrong file content: %q", b)
+	}
+
+	names, err := f.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected 2 backups, received: %v", names)
+	}
+
+	expected := filepath.Join(dir, "app-2020-01-01T00-00-04.000.log")
+	if names[0] != expected {
+		t.Errorf("wrong backup name. Expected: %s, received: %s", expected, names[0])
+	}
+	if b, _ := os.ReadFile(names[0]); string(b) != "line3\n" {
+		t.Errorf("wrong backup content: %q", b)
+	}
+	if b, _ := os.ReadFile(names[1]); string(b) != "line2\n" {
+		t.Errorf("wrong backup content: %q", b)
+	}
+}
+
+func TestRotatingFile_interval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	now := time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)
+	f, err := NewRotatingFile(path, RotateConfig{Interval: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.now = func() time.Time { return now }
+	f.openedAt = now
+
+	_, _ = f.Write([]byte("first\n"))
+	now = now.Add(30 * time.Minute)
+	_, _ = f.Write([]byte("second\n"))
+
+	if names, _ := f.backups(); len(names) != 0 {
+		t.Fatalf("unexpected rotation: %v", names)
+	}
+
+	now = now.Add(time.Hour)
+	_, _ = f.Write([]byte("third\n"))
+
+	names, _ := f.backups()
+	if len(names) != 1 {
+		t.Fatalf("expected 1 backup, received: %v", names)
+	}
+	if b, _ := os.ReadFile(names[0]); string(b) != "first\nsecond\n" {
+		t.Errorf("wrong backup content: %q", b)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "third\n" {
+		t.Errorf("wrong file content: %q", b)
+	}
+}
+
+func TestRotatingFile_compress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = f.Write([]byte("test\n"))
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names, _ := f.backups()
+	if len(names) != 1 || !strings.HasSuffix(names[0], ".log.gz") {
+		t.Fatalf("expected compressed backup, received: %v", names)
+	}
+
+	file, err := os.Open(names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(gz); string(b) != "test\n" {
+		t.Errorf("wrong backup content: %q", b)
+	}
+}
+
+func TestRotatingFile_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	_, _ = f.Write([]byte("first\n"))
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte("second\n"))
+
+	if b, _ := os.ReadFile(path + ".1"); string(b) != "first\n" {
+		t.Errorf("wrong moved file content: %q", b)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "second\n" {
+		t.Errorf("wrong file content: %q", b)
+	}
+}
+
+func TestRotatingFile_Logg(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := New(nil)
+	l.SetFlags(0)
+	l.ToggleColor(false)
+	l.SetWriter(f)
+	l.SetExitFunc(func(int) {})
+	l.Fatal("test")
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Sync(); err != os.ErrClosed {
+		t.Errorf("expected closed error, received: %v", err)
+	}
+
+	if b, _ := os.ReadFile(path); !bytes.Equal(b, []byte("FTL test\n")) {
+		t.Errorf("wrong file content: %q", b)
+	}
+}
+
+func TestRotatingFile_renameError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	errRename := errors.New("rename error")
+	f.rename = func(string, string) error { return errRename }
+
+	_, _ = f.Write([]byte("line1\n"))
+	if err := f.Rotate(); !errors.Is(err, errRename) {
+		t.Errorf("expected rename error, received: %v", err)
+	}
+	for _, s := range []string{"line2\n", "line3\n"} {
+		if _, err := f.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if b, _ := os.ReadFile(path); string(b) != "line1\nline2\nline3\n" {
+		t.Errorf("wrong file content: %q", b)
+	}
+}
+
+func TestRotatingFile_closed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte("test\n"))
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("unexpected error of the second close: %v", err)
+	}
+	if _, err := f.Write([]byte("test\n")); err != os.ErrClosed {
+		t.Errorf("expected closed error of write, received: %v", err)
+	}
+	if err := f.Rotate(); err != os.ErrClosed {
+		t.Errorf("expected closed error of rotate, received: %v", err)
+	}
+	if err := f.Reopen(); err != os.ErrClosed {
+		t.Errorf("expected closed error of reopen, received: %v", err)
+	}
+
+	if b, _ := os.ReadFile(path); string(b) != "test\n" {
+		t.Errorf("wrong file content: %q", b)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package logg
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyReopen reopens the file on SIGHUP. Returns a function which stops it.
+func notifyReopen(f *RotatingFile) func() {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := f.Reopen(); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "logg: could not reopen %s: %v\n", f.path, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
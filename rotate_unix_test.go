@@ -0,0 +1,50 @@
+//go:build unix
+
+package logg
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_SIGHUP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, RotateConfig{ReopenOnSignal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	_, _ = f.Write([]byte("first\n"))
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100 && !fileExists(path); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !fileExists(path) {
+		t.Fatal("file was not reopened")
+	}
+}
+
+func TestRotatingFile_CloseSignal(t *testing.T) {
+	f, err := NewRotatingFile(filepath.Join(t.TempDir(), "app.log"), RotateConfig{ReopenOnSignal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("unexpected error of the second close: %v", err)
+	}
+}
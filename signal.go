@@ -0,0 +1,82 @@
+package logg
+
+import "sync"
+
+// levelToggler changes minimum levels of loggers on signals.
+type levelToggler struct {
+	names []string
+
+	mu         sync.Mutex
+	configured map[string]Level // levels before the first change by name
+}
+
+func newLevelToggler(names []string) *levelToggler {
+	if len(names) == 0 {
+		names = []string{GlobalName}
+	}
+
+	return &levelToggler{names: names, configured: map[string]Level{}}
+}
+
+// lower sets the next less severe level, the configured level
+// follows the least severe level.
+func (t *levelToggler) lower(signal string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, name := range t.names {
+		l, ok := Logger(name)
+		if !ok {
+			continue
+		}
+
+		from := l.Level()
+		configured, changed := t.configured[name]
+		if !changed {
+			configured = from
+			t.configured[name] = from
+		}
+
+		to := lessSevere(from)
+		if to == from {
+			to = configured
+		}
+
+		l.MinLevel(to)
+		l.write(1, Empty, []byte("log level changed"),
+			String("logger", name), String("from", from.String()), String("to", to.String()), String("signal", signal))
+	}
+}
+
+// restore sets the level which was set before the first change.
+func (t *levelToggler) restore(signal string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, name := range t.names {
+		configured, changed := t.configured[name]
+		l, ok := Logger(name)
+		if !changed || !ok {
+			continue
+		}
+		delete(t.configured, name)
+
+		from := l.Level()
+		l.MinLevel(configured)
+		l.write(1, Empty, []byte("log level restored"),
+			String("logger", name), String("from", from.String()), String("to", configured.String()), String("signal", signal))
+	}
+}
+
+// lessSevere returns the closest less severe registered level
+// or the level if there is no such level.
+func lessSevere(level Level) Level {
+	list := loadLevels().list
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].level < level {
+			return list[i].level
+		}
+	}
+
+	return level
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package logg
+
+// HandleLevelSignals does nothing, SIGUSR1 and SIGUSR2 are not supported
+// on this platform.
+func HandleLevelSignals(names ...string) (stop func()) {
+	return func() {}
+}
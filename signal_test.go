@@ -0,0 +1,20 @@
+package logg
+
+import "testing"
+
+func Test_lessSevere(t *testing.T) {
+	tests := map[Level]Level{
+		Fatal:   Panic,
+		audit:   Error,
+		Warning: notice,
+		Info:    Debug,
+		Trace:   Trace,
+		-100:    -100,
+	}
+
+	for level, expected := range tests {
+		if l := lessSevere(level); l != expected {
+			t.Errorf("wrong level after %d. Expected: %d, received: %d", level, expected, l)
+		}
+	}
+}
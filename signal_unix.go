@@ -0,0 +1,43 @@
+//go:build unix
+
+package logg
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HandleLevelSignals changes minimum levels of the loggers on signals.
+// SIGUSR1 sets the next less severe level (Info → Debug → Trace → Info),
+// SIGUSR2 restores the level which was set before the first change.
+// Loggers are registered names or GlobalName, the global logger
+// if no names are provided. Each change is written by the logger.
+// Returns a function which stops handling signals.
+func HandleLevelSignals(names ...string) (stop func()) {
+	t := newLevelToggler(names)
+
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for {
+			select {
+			case sig := <-c:
+				if sig == syscall.SIGUSR1 {
+					t.lower("SIGUSR1")
+				} else {
+					t.restore("SIGUSR2")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
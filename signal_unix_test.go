@@ -0,0 +1,64 @@
+//go:build unix
+
+package logg
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func waitLevel(t *testing.T, l *Logg, level Level) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for l.Level() != level && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if l.Level() != level {
+		t.Fatalf("wrong level. Expected: %s, received: %s", level, l.Level())
+	}
+}
+
+func TestHandleLevelSignals(t *testing.T) {
+	out := newGateWriter()
+	close(out.gate)
+	logger := New(out)
+	logger.SetFlags(0)
+	logger.ToggleColor(false)
+	RegisterLogger("signals", logger)
+	defer RegisterLogger("signals", nil)
+	child := logger.With(String("id", "1"))
+
+	stop := HandleLevelSignals("signals")
+	defer stop()
+
+	for _, level := range []Level{Debug, Trace, Info, Debug} {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatal(err)
+		}
+		waitLevel(t, child, level)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitLevel(t, logger, Info)
+
+	expected := []string{
+		"log level changed logger=signals from=INF to=DBG signal=SIGUSR1",
+		"log level changed logger=signals from=DBG to=TRC signal=SIGUSR1",
+		"log level changed logger=signals from=TRC to=INF signal=SIGUSR1",
+		"log level changed logger=signals from=INF to=DBG signal=SIGUSR1",
+		"log level restored logger=signals from=DBG to=INF signal=SIGUSR2",
+	}
+	deadline := time.Now().Add(time.Second)
+	for strings.Count(out.String(), "\n") < len(expected) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if output := out.String(); output != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("wrong output:\n%s", output)
+	}
+}
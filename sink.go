@@ -0,0 +1,59 @@
+package logg
+
+import "io"
+
+// Sink is an additional output of a logger with its own writer,
+// format, minimum level and color setting.
+type Sink struct {
+	Writer    io.Writer
+	Format    Format
+	Formatter Formatter // used instead of Format if set, doesn't need RegisterFormat
+	MinLevel  Level     // Info by default
+	Color     bool
+}
+
+// sink is an additional output of the logger.
+type sink struct {
+	minLevel  Level
+	out       *syncWriter
+	format    Format
+	formatter Formatter
+	color     bool
+}
+
+// AddSink adds an output to the logger. Messages are written to the logger
+// writer with the logger settings and to each sink with the sink settings.
+// A message is built once for each distinct format and color.
+//
+//	log := logg.New(os.Stdout) // pretty, info and above
+//	log.AddSink(logg.Sink{Writer: file, Format: logg.Json, MinLevel: logg.Debug})
+func (l *Logg) AddSink(s Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sinks := make([]sink, len(l.sinks), len(l.sinks)+1)
+	copy(sinks, l.sinks)
+
+	l.sinks = append(sinks, sink{
+		minLevel:  s.MinLevel,
+		out:       l.syncWriter(s.Writer),
+		format:    s.Format,
+		formatter: s.Formatter,
+		color:     s.Color,
+	})
+}
+
+// RemoveSink removes sinks with the writer.
+func (l *Logg) RemoveSink(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sinks := make([]sink, 0, len(l.sinks))
+	for _, s := range l.sinks {
+		if !sameWriter(s.out.w, w) {
+			sinks = append(sinks, s)
+		}
+	}
+
+	l.sinks = sinks
+}
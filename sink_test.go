@@ -0,0 +1,130 @@
+package logg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type countingFormatter struct {
+	calls int64
+}
+
+func (f *countingFormatter) Format(dst []byte, e *Entry) []byte {
+	atomic.AddInt64(&f.calls, 1)
+	return append(dst, e.Message...)
+}
+
+var (
+	counting       = &countingFormatter{}
+	countingFormat = RegisterFormat(counting)
+)
+
+func TestLogg_AddSink(t *testing.T) {
+	term, file, errs := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	logger := New(term)
+	logger.flags = 0
+	logger.AddSink(Sink{Writer: file, Format: Json, MinLevel: Debug})
+	logger.AddSink(Sink{Writer: errs, Format: Logfmt, MinLevel: Error})
+
+	logger.Debugw("debug", "id", 1)
+	logger.With(String("service", "api")).Info("info")
+	logger.Error("error")
+
+	expected := fmt.Sprintf("%s%s[1mINF%s%s info service=api\n", generate(HiYellow), escape, escapeClose, escapeClose) +
+		fmt.Sprintf("%s%s[1mERR%s%s error\n", generate(Red), escape, escapeClose, escapeClose)
+	if output := term.String(); output != expected {
+		t.Errorf("wrong terminal output. Expected: %q, received: %q", expected, output)
+	}
+
+	expected = `{"level": "DBG", "message": "debug", "id": 1}` + "\n" +
+		`{"level": "INF", "message": "info", "service": "api"}` + "\n" +
+		`{"level": "ERR", "message": "error"}` + "\n"
+	if output := file.String(); output != expected {
+		t.Errorf("wrong file output. Expected: %q, received: %q", expected, output)
+	}
+
+	if output := errs.String(); output != "level=error msg=error\n" {
+		t.Errorf("wrong error output: %q", output)
+	}
+
+	file.Reset()
+	logger.RemoveSink(file)
+	logger.Error("error")
+	if file.Len() != 0 {
+		t.Errorf("removed sink must not be written: %q", file.String())
+	}
+	if strings.Count(errs.String(), "\n") != 2 {
+		t.Errorf("sink must be written after removal of another sink: %q", errs.String())
+	}
+}
+
+func TestLogg_AddSink_buildOnce(t *testing.T) {
+	a, b, c := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	logger := New(a)
+	logger.flags = 0
+	logger.SetFormat(countingFormat)
+	logger.AddSink(Sink{Writer: b, Format: countingFormat})
+	logger.AddSink(Sink{Writer: c, Format: countingFormat, Color: true})
+
+	atomic.StoreInt64(&counting.calls, 0)
+	logger.Info("test")
+
+	// logger and the first sink have the same format and color
+	if calls := atomic.LoadInt64(&counting.calls); calls != 2 {
+		t.Errorf("expected 2 format calls, received: %d", calls)
+	}
+	for _, buf := range []*bytes.Buffer{a, b, c} {
+		if output := buf.String(); output != "test\n" {
+			t.Errorf("wrong output: %q", output)
+		}
+	}
+}
+
+func TestLogg_AddSink_Formatter(t *testing.T) {
+	a, b, c := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	logger := New(a)
+	logger.flags = 0
+	logger.color = false
+
+	first, second := &countingFormatter{}, &countingFormatter{}
+	logger.AddSink(Sink{Writer: b, Formatter: first})
+	logger.AddSink(Sink{Writer: c, Formatter: second, Format: Json})
+
+	logger.Info("test")
+
+	if output := a.String(); output != "INF test\n" {
+		t.Errorf("wrong logger output: %q", output)
+	}
+	for _, buf := range []*bytes.Buffer{b, c} {
+		if output := buf.String(); output != "test\n" {
+			t.Errorf("wrong sink output: %q", output)
+		}
+	}
+	if first.calls != 1 || second.calls != 1 {
+		t.Errorf("each sink formatter must be called once: %d, %d", first.calls, second.calls)
+	}
+}
+
+func TestLogg_AddSink_enabled(t *testing.T) {
+	out, sink := new(bytes.Buffer), new(bytes.Buffer)
+	logger := New(out)
+	logger.flags = 0
+	logger.color = false
+	logger.MinLevel(Error)
+	logger.AddSink(Sink{Writer: sink, MinLevel: Debug})
+
+	if !logger.enabled(Debug) {
+		t.Error("debug level must be enabled by the sink")
+	}
+
+	logger.Debug("debug")
+	if out.Len() != 0 {
+		t.Errorf("debug message must not be written to the logger writer: %q", out.String())
+	}
+	if output := sink.String(); output != "DBG debug\n" {
+		t.Errorf("wrong sink output: %q", output)
+	}
+}
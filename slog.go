@@ -0,0 +1,159 @@
+package logg
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler which writes records with a Logg.
+// Logg levels have the same values as slog levels (Debug = -4, Info = 0,
+// Warning = 4, Error = 8), other slog levels are written with the closest
+// less severe level label.
+type SlogHandler struct {
+	l      *Logg
+	groups []slogGroup // groups opened with WithGroup
+}
+
+// slogGroup contains attributes added after the group was opened.
+type slogGroup struct {
+	name   string
+	fields []Field
+}
+
+// NewSlogHandler returns a slog.Handler which writes records with l.
+//
+//	logger := slog.New(logg.NewSlogHandler(logg.New(os.Stdout)))
+func NewSlogHandler(l *Logg) *SlogHandler {
+	return &SlogHandler{l: l}
+}
+
+// Enabled reports whether the logger writes records with the level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.enabled(Level(level))
+}
+
+// Handle writes the record with fields registered for the context.
+// Records are formatted even without a message and attributes.
+// The caller is taken from the record PC. The time is taken from the record
+// unless the logger clock is set, records with a zero time are written
+// without the time.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	m := h.l.newMessage(Level(r.Level), ContextCallDepth)
+	if m == nil {
+		return nil
+	}
+	m.pc, m.record = r.PC, true
+	if r.Time.IsZero() {
+		m.flags &^= Ldate | Ltime | Lmicroseconds
+	} else {
+		m.time = r.Time
+	}
+
+	if ctx != nil {
+		m.fields = appendContextFields(m.fields, ctx)
+	}
+
+	if len(h.groups) == 0 {
+		r.Attrs(func(a slog.Attr) bool {
+			m.fields = appendSlogAttr(m.fields, a)
+			return true
+		})
+	} else {
+		fields := make([]Field, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			fields = appendSlogAttr(fields, a)
+			return true
+		})
+
+		// wrap fields into groups from the innermost one,
+		// empty groups are omitted
+		for i := len(h.groups) - 1; i >= 0; i-- {
+			g := h.groups[i]
+			fields = append(g.fields[:len(g.fields):len(g.fields)], fields...)
+			if len(fields) != 0 {
+				fields = []Field{Group(g.name, fields...)}
+			}
+		}
+		m.fields = append(m.fields, fields...)
+	}
+
+	m.write(m.build([]byte(r.Message)))
+	m.put()
+
+	return nil
+}
+
+// WithAttrs returns a handler which writes attributes with each record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, a)
+	}
+
+	if len(h.groups) == 0 {
+		return &SlogHandler{l: h.l.With(fields...)}
+	}
+
+	groups := make([]slogGroup, len(h.groups))
+	copy(groups, h.groups)
+	last := &groups[len(groups)-1]
+	last.fields = append(last.fields[:len(last.fields):len(last.fields)], fields...)
+
+	return &SlogHandler{l: h.l, groups: groups}
+}
+
+// WithGroup returns a handler which writes all following attributes
+// in the group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]slogGroup, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+
+	return &SlogHandler{l: h.l, groups: append(groups, slogGroup{name: name})}
+}
+
+// appendSlogAttr converts the attribute to a field.
+func appendSlogAttr(dst []Field, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		return append(dst, String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(dst, Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(dst, Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(dst, Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(dst, Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(dst, Duration(a.Key, v.Duration()))
+	case slog.KindTime:
+		return append(dst, Time(a.Key, v.Time()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return dst
+		}
+
+		fields := make([]Field, 0, len(attrs))
+		for _, ga := range attrs {
+			fields = appendSlogAttr(fields, ga)
+		}
+		return append(dst, Group(a.Key, fields...))
+	}
+
+	return append(dst, Any(a.Key, v.Any()))
+}
@@ -0,0 +1,149 @@
+package logg
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	tests := map[string]struct {
+		log    func(l *slog.Logger)
+		pretty string
+		json   string
+	}{
+		"message": {
+			log:    func(l *slog.Logger) { l.Info("test") },
+			pretty: "INF test",
+			json:   `{"level": "INF", "message": "test"}`,
+		},
+		"empty message": {
+			log:    func(l *slog.Logger) { l.Warn("") },
+			pretty: "WRN",
+			json:   `{"level": "WRN"}`,
+		},
+		"levels": {
+			log:    func(l *slog.Logger) { l.Warn("test"); l.Error("test"); l.Debug("test") },
+			pretty: "WRN test\nERR test",
+			json:   `{"level": "WRN", "message": "test"}` + "\n" + `{"level": "ERR", "message": "test"}`,
+		},
+		"attrs": {
+			log: func(l *slog.Logger) {
+				l.Info("test", "user", "bob", slog.Int("id", 1), slog.Duration("latency", time.Second))
+			},
+			pretty: "INF test user=bob id=1 latency=1s",
+			json:   `{"level": "INF", "message": "test", "user": "bob", "id": 1, "latency": "1s"}`,
+		},
+		"group attr": {
+			log: func(l *slog.Logger) {
+				l.Info("test", slog.Group("req", "method", "GET", slog.Group("url", "path", "/")))
+			},
+			pretty: "INF test req.method=GET req.url.path=/",
+			json:   `{"level": "INF", "message": "test", "req": {"method": "GET", "url": {"path": "/"}}}`,
+		},
+		"empty group": {
+			log:    func(l *slog.Logger) { l.Info("test", slog.Group("req"), slog.Attr{}) },
+			pretty: "INF test",
+			json:   `{"level": "INF", "message": "test"}`,
+		},
+		"inline group": {
+			log:    func(l *slog.Logger) { l.Info("test", slog.Group("", "a", 1)) },
+			pretty: "INF test a=1",
+			json:   `{"level": "INF", "message": "test", "a": 1}`,
+		},
+		"with attrs": {
+			log:    func(l *slog.Logger) { l.With("service", "api").Info("test", "id", 1) },
+			pretty: "INF test service=api id=1",
+			json:   `{"level": "INF", "message": "test", "service": "api", "id": 1}`,
+		},
+		"with group": {
+			log: func(l *slog.Logger) {
+				l.With("service", "api").WithGroup("req").With("id", 1).WithGroup("user").Info("test", "name", "bob")
+			},
+			pretty: "INF test service=api req.id=1 req.user.name=bob",
+			json:   `{"level": "INF", "message": "test", "service": "api", "req": {"id": 1, "user": {"name": "bob"}}}`,
+		},
+		"with empty group": {
+			log:    func(l *slog.Logger) { l.WithGroup("req").With("id", 1).WithGroup("user").Info("test") },
+			pretty: "INF test req.id=1",
+			json:   `{"level": "INF", "message": "test", "req": {"id": 1}}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := New(buf)
+			logger.flags = 0
+			logger.color = false
+
+			tc.log(slog.New(NewSlogHandler(logger)))
+			if output := buf.String(); output != tc.pretty+"\n" {
+				t.Errorf("wrong pretty output. Expected: %s, received: %s", tc.pretty, output)
+			}
+
+			buf.Reset()
+			logger.SetFormat(Json)
+			tc.log(slog.New(NewSlogHandler(logger)))
+			if output := buf.String(); output != tc.json+"\n" {
+				t.Errorf("wrong json output. Expected: %s, received: %s", tc.json, output)
+			}
+		})
+	}
+}
+
+func TestSlogHandler_caller(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = Lshortfile
+	logger.color = false
+
+	slog.New(NewSlogHandler(logger)).Info("test")
+	_, _, line, _ := runtime.Caller(0)
+
+	expected := "slog_test.go:" + strconv.Itoa(line-1) + " INF test"
+	if output := readFromBuffer(buf); output != expected {
+		t.Errorf("wrong caller. Expected: %s, received: %s", expected, output)
+	}
+}
+
+func TestSlogHandler_Enabled(t *testing.T) {
+	logger := New(nil)
+	logger.MinLevel(Warning)
+	h := NewSlogHandler(logger)
+
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("info level must be disabled")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("error level must be enabled")
+	}
+}
+
+func TestSlogHandler_time(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf)
+	logger.flags = LstdFlags | LUTC
+	logger.color = false
+	h := NewSlogHandler(logger)
+
+	r := slog.NewRecord(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelInfo, "test", 0)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if output := readFromBuffer(buf); output != "2020-01-02 03:04:05 INF test" {
+		t.Errorf("wrong record time: %s", output)
+	}
+
+	r = slog.NewRecord(time.Time{}, slog.LevelInfo, "test", 0)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if output := readFromBuffer(buf); output != "INF test" {
+		t.Errorf("zero time must be omitted: %s", output)
+	}
+}
@@ -0,0 +1,443 @@
+package logg
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Facility defines a syslog facility.
+type Facility int
+
+// Syslog facilities
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthpriv
+	FacilityFtp
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Syslog severities
+const (
+	severityEmerg = iota
+	severityAlert
+	severityCrit
+	severityErr
+	severityWarning
+	severityNotice
+	severityInfo
+	severityDebug
+)
+
+const (
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	bsdTimeFormat    = "Jan _2 15:04:05"
+
+	// DefaultSyslogSDID is a structured data ID of fields in RFC 5424 messages.
+	// 32473 is the private enterprise number reserved for documentation.
+	DefaultSyslogSDID = "logg@32473"
+)
+
+// localSyslogPaths are sockets of the local syslog daemon.
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig defines a syslog connection and message header.
+type SyslogConfig struct {
+	Network  string   // unixgram, unix, udp or tcp; the local syslog daemon if empty
+	Address  string   // socket path or host:port
+	Facility Facility // FacilityUser if 0
+	AppName  string   // program name by default
+	Hostname string   // os.Hostname by default
+	SDID     string   // structured data ID of fields, DefaultSyslogSDID by default
+	RFC3164  bool     // legacy BSD format instead of RFC 5424
+}
+
+// Syslog is a Formatter which formats entries as syslog messages and
+// an io.Writer which sends them to a syslog daemon. Fields are written
+// as structured data in RFC 5424 and as key=value pairs in RFC 3164.
+// The connection is reestablished on write errors.
+//
+//	s, err := logg.NewSyslog(logg.SyslogConfig{Facility: logg.FacilityLocal0})
+//	log.AddSink(s.Sink(logg.Info))
+type Syslog struct {
+	cfg SyslogConfig
+	pid string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	network string // network of the connection, used for framing
+	buf     []byte // framed stream message
+}
+
+// NewSyslog connects to the syslog daemon.
+func NewSyslog(cfg SyslogConfig) (*Syslog, error) {
+	if cfg.Facility == FacilityKern {
+		cfg.Facility = FacilityUser
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = filepath.Base(os.Args[0])
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname = "localhost"
+	}
+	if cfg.SDID == "" {
+		cfg.SDID = DefaultSyslogSDID
+	}
+
+	s := &Syslog{
+		cfg: cfg,
+		pid: strconv.Itoa(os.Getpid()),
+	}
+
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Sink returns a sink which writes messages with the level and above to syslog.
+func (s *Syslog) Sink(level Level) Sink {
+	return Sink{Writer: s, Formatter: s, MinLevel: level}
+}
+
+// FormatsEmpty marks the syslog as an EmptyFormatter, so empty messages
+// are sent with the header too.
+func (s *Syslog) FormatsEmpty() {}
+
+// Format appends the entry as a syslog message to dst.
+func (s *Syslog) Format(dst []byte, e *Entry) []byte {
+	t := e.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	dst = append(dst, '<')
+	dst = strconv.AppendInt(dst, int64(s.cfg.Facility)*8+int64(syslogSeverity(e.Level)), 10)
+	dst = append(dst, '>')
+
+	if s.cfg.RFC3164 {
+		return s.appendBSD(dst, t, e)
+	}
+
+	dst = append(dst, "1 "...)
+	dst = t.AppendFormat(dst, syslogTimeFormat)
+	dst = append(dst, ' ')
+	dst = appendSyslogHeader(dst, s.cfg.Hostname, 255)
+	dst = append(dst, ' ')
+	dst = appendSyslogHeader(dst, s.cfg.AppName, 48)
+	dst = append(dst, ' ')
+	dst = append(dst, s.pid...)
+	dst = append(dst, " - "...)
+
+	dst = s.appendStructuredData(dst, e)
+
+	if len(e.Message) != 0 {
+		dst = append(dst, ' ')
+		dst = append(dst, e.Message...)
+	}
+
+	return dst
+}
+
+// appendBSD appends the RFC 3164 header and the message to dst.
+func (s *Syslog) appendBSD(dst []byte, t time.Time, e *Entry) []byte {
+	dst = t.AppendFormat(dst, bsdTimeFormat)
+	dst = append(dst, ' ')
+	dst = append(dst, s.cfg.Hostname...)
+	dst = append(dst, ' ')
+	dst = append(dst, s.cfg.AppName...)
+	dst = append(dst, '[')
+	dst = append(dst, s.pid...)
+	dst = append(dst, "]:"...)
+
+	if e.Flags&(Lshortfile|Llongfile) != 0 {
+		dst = append(dst, ' ')
+		dst = append(dst, e.File...)
+		dst = append(dst, ':')
+		dst = strconv.AppendInt(dst, int64(e.Line), 10)
+	}
+
+	if len(e.Message) != 0 {
+		dst = append(dst, ' ')
+		dst = append(dst, e.Message...)
+	}
+
+	b, fields := e.boundFields()
+	if b != nil && len(b.pretty) != 0 {
+		dst = append(dst, ' ')
+		dst = append(dst, b.pretty...)
+	}
+
+	return appendPrettyFields(dst, fields)
+}
+
+// appendStructuredData appends the caller and fields as an SD-ELEMENT,
+// or "-" if there is nothing to write.
+func (s *Syslog) appendStructuredData(dst []byte, e *Entry) []byte {
+	hasCaller := e.Flags&(Lshortfile|Llongfile) != 0
+	if !hasCaller && len(e.Fields) == 0 {
+		return append(dst, '-')
+	}
+
+	start := len(dst)
+	dst = append(dst, '[')
+	dst = append(dst, s.cfg.SDID...)
+
+	if hasCaller {
+		dst = append(dst, ` file="`...)
+		dst = appendSDValue(dst, e.File)
+		dst = append(dst, `" line="`...)
+		dst = strconv.AppendInt(dst, int64(e.Line), 10)
+		dst = append(dst, '"')
+	}
+
+	n := len(dst)
+	dst = appendSDParams(dst, "", e.Fields)
+	if !hasCaller && len(dst) == n {
+		return append(dst[:start], '-') // only empty groups
+	}
+
+	return append(dst, ']')
+}
+
+// appendSDParams appends fields as SD-PARAMs. Groups are flattened
+// to prefix.key params.
+func appendSDParams(dst []byte, prefix string, fields []Field) []byte {
+	for _, f := range fields {
+		if f.typ == groupType {
+			p := prefix
+			switch {
+			case f.Key == "":
+			case prefix == "":
+				p = f.Key
+			default:
+				p = prefix + "." + f.Key
+			}
+			dst = appendSDParams(dst, p, f.val.([]Field))
+			continue
+		}
+
+		dst = append(dst, ' ')
+		start := len(dst)
+		if prefix != "" {
+			dst = appendSDName(dst, prefix)
+			dst = append(dst, '.')
+		}
+		dst = appendSDName(dst, f.Key)
+		if len(dst)-start > 32 {
+			dst = dst[:start+32]
+		}
+
+		dst = append(dst, `="`...)
+		if str, ok := f.stringValue(); ok {
+			dst = appendSDValue(dst, str)
+		} else {
+			dst = f.appendValue(dst)
+		}
+		dst = append(dst, '"')
+	}
+
+	return dst
+}
+
+// appendSDName appends a param name, invalid characters are replaced with '_'.
+func appendSDName(dst []byte, name string) []byte {
+	if name == "" {
+		return append(dst, '_')
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		dst = append(dst, c)
+	}
+
+	return dst
+}
+
+// appendSDValue appends a param value with escaped '"', '\' and ']'.
+func appendSDValue(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '"' || c == '\\' || c == ']' {
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, s[i])
+	}
+
+	return dst
+}
+
+// appendSyslogHeader appends a header field which must be printable
+// ASCII without spaces, "-" if the value is empty.
+func appendSyslogHeader(dst []byte, s string, max int) []byte {
+	if s == "" {
+		return append(dst, '-')
+	}
+	if len(s) > max {
+		s = s[:max]
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f {
+			c = '_'
+		}
+		dst = append(dst, c)
+	}
+
+	return dst
+}
+
+// syslogSeverity maps the level to a syslog severity.
+// Levels between built-in levels have the severity of the less severe level,
+// levels between Info and Warning are mapped to notice.
+func syslogSeverity(level Level) int {
+	switch {
+	case level == Empty:
+		return severityInfo
+	case level < Info:
+		return severityDebug
+	case level == Info:
+		return severityInfo
+	case level < Warning:
+		return severityNotice
+	case level < Error:
+		return severityWarning
+	case level < Panic:
+		return severityErr
+	case level < Fatal:
+		return severityCrit
+	}
+
+	return severityAlert
+}
+
+// Write sends the message to the syslog daemon. If the write fails,
+// the connection is reestablished and the message is sent again.
+func (s *Syslog) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(b)
+	if s.conn != nil {
+		if err := s.send(b); err == nil {
+			return n, nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+
+	if err := s.connect(); err != nil {
+		return 0, err
+	}
+	if err := s.send(b); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Close closes the connection.
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// send writes the message to the connection. A message is sent without
+// the trailing new line in datagrams and with the octet-counting framing
+// of RFC 6587 (length, space, message) in tcp streams. Unix streams are
+// framed by new lines, new lines in the message are replaced with spaces.
+func (s *Syslog) send(b []byte) error {
+	if len(b) != 0 && b[len(b)-1] == '\n' {
+		b = b[:len(b)-1]
+	}
+
+	switch s.network {
+	case "unixgram", "udp", "udp4", "udp6":
+		return write(s.conn, b)
+	case "tcp", "tcp4", "tcp6":
+		s.buf = strconv.AppendInt(s.buf[:0], int64(len(b)), 10)
+		s.buf = append(s.buf, ' ')
+		s.buf = append(s.buf, b...)
+	default:
+		s.buf = append(s.buf[:0], b...)
+		for i, c := range s.buf {
+			if c == '\n' {
+				s.buf[i] = ' '
+			}
+		}
+		s.buf = append(s.buf, '\n')
+	}
+
+	return write(s.conn, s.buf)
+}
+
+// connect connects to the configured address or to the local syslog daemon.
+func (s *Syslog) connect() error {
+	if s.cfg.Network != "" {
+		conn, err := net.Dial(s.cfg.Network, s.cfg.Address)
+		if err != nil {
+			return err
+		}
+		s.conn, s.network = conn, s.cfg.Network
+		return nil
+	}
+
+	paths := localSyslogPaths
+	if s.cfg.Address != "" {
+		paths = []string{s.cfg.Address}
+	}
+
+	for _, path := range paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, path)
+			if err == nil {
+				s.conn, s.network = conn, network
+				return nil
+			}
+		}
+	}
+
+	return errors.New("logg: local syslog daemon is not available")
+}
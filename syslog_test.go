@@ -0,0 +1,233 @@
+package logg
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listenUnixgram(t *testing.T, path string) *net.UnixConn {
+	t.Helper()
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram is not supported: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func readDatagram(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(buf[:n])
+}
+
+func TestSyslog_Format(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	listenUnixgram(t, path)
+	pid := strconv.Itoa(os.Getpid())
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		cfg      SyslogConfig
+		entry    Entry
+		expected string
+	}{
+		"rfc5424": {
+			cfg:      SyslogConfig{Facility: FacilityLocal0},
+			entry:    Entry{Time: ts, Level: Info, Message: []byte("test")},
+			expected: "<134>1 2020-01-02T03:04:05.000000Z host app " + pid + " - - test",
+		},
+		"rfc5424 fields": {
+			entry: Entry{Time: ts, Level: Error, Message: []byte("test"), Fields: []Field{
+				String("quote", `a"b]c\`), Int("bad key", 1), Group("req", String("method", "GET")), Err(errors.New("failed")),
+			}},
+			expected: "<11>1 2020-01-02T03:04:05.000000Z host app " + pid + ` - [logg@32473 quote="a\"b\]c\\" bad_key="1" req.method="GET" error="failed"] test`,
+		},
+		"rfc5424 empty group": {
+			entry:    Entry{Time: ts, Level: Debug, Fields: []Field{Group("req")}},
+			expected: "<15>1 2020-01-02T03:04:05.000000Z host app " + pid + " - -",
+		},
+		"rfc5424 caller": {
+			cfg:      SyslogConfig{SDID: "app@1"},
+			entry:    Entry{Time: ts, Level: Warning, Message: []byte("test"), Flags: Lshortfile, File: "main.go", Line: 10},
+			expected: "<12>1 2020-01-02T03:04:05.000000Z host app " + pid + ` - [app@1 file="main.go" line="10"] test`,
+		},
+		"rfc3164": {
+			cfg:      SyslogConfig{Facility: FacilityDaemon, RFC3164: true},
+			entry:    Entry{Time: ts, Level: Fatal, Message: []byte("test"), Fields: []Field{Int("id", 1)}},
+			expected: "<25>Jan  2 03:04:05 host app[" + pid + "]: test id=1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.cfg.Network, tc.cfg.Address = "unixgram", path
+			tc.cfg.Hostname, tc.cfg.AppName = "host", "app"
+
+			s, err := NewSyslog(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			if output := string(s.Format(nil, &tc.entry)); output != tc.expected {
+				t.Errorf("wrong message.\nExpected: %s\nReceived: %s", tc.expected, output)
+			}
+		})
+	}
+}
+
+func Test_syslogSeverity(t *testing.T) {
+	tests := map[Level]int{
+		Empty:   severityInfo,
+		Trace:   severityDebug,
+		Debug:   severityDebug,
+		Info:    severityInfo,
+		2:       severityNotice,
+		Warning: severityWarning,
+		Error:   severityErr,
+		Panic:   severityCrit,
+		Fatal:   severityAlert,
+	}
+
+	for level, expected := range tests {
+		if severity := syslogSeverity(level); severity != expected {
+			t.Errorf("wrong severity of %d. Expected: %d, received: %d", level, expected, severity)
+		}
+	}
+}
+
+func TestSyslog_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	conn := listenUnixgram(t, path)
+
+	s, err := NewSyslog(SyslogConfig{Network: "unixgram", Address: path, Hostname: "host", AppName: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	logger := New(nil)
+	logger.SetFlags(0)
+	logger.AddSink(s.Sink(Debug))
+
+	logger.Debugw("test", "id", 1)
+	suffix := " host app " + strconv.Itoa(os.Getpid()) + ` - [logg@32473 id="1"] test`
+	if output := readDatagram(t, conn); !strings.HasPrefix(output, "<15>1 ") || !strings.HasSuffix(output, suffix) {
+		t.Errorf("wrong message: %q", output)
+	}
+
+	logger.Warn("")
+	if output := readDatagram(t, conn); !strings.HasPrefix(output, "<12>1 ") || !strings.HasSuffix(output, " host app "+strconv.Itoa(os.Getpid())+" - -") {
+		t.Errorf("wrong empty message: %q", output)
+	}
+
+	// the daemon restarts, the message is sent after reconnect
+	_ = conn.Close()
+	_ = os.Remove(path)
+	conn = listenUnixgram(t, path)
+
+	logger.Error("reconnect")
+	if output := readDatagram(t, conn); !strings.HasSuffix(output, " - - reconnect") {
+		t.Errorf("wrong message after reconnect: %q", output)
+	}
+}
+
+func TestSyslog_stream(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp is not supported: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 4096)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _ := conn.Read(buf)
+		received <- string(buf[:n])
+	}()
+
+	s, err := NewSyslog(SyslogConfig{Network: "tcp", Address: ln.Addr().String(), RFC3164: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	logger := New(nil)
+	logger.AddSink(s.Sink(Info))
+	logger.Info("multi\nline")
+
+	output := <-received
+	length, msg, _ := strings.Cut(output, " ")
+	if n, err := strconv.Atoi(length); err != nil || n != len(msg) {
+		t.Errorf("stream message must be prefixed with the length: %q", output)
+	}
+	if !strings.HasPrefix(msg, "<14>") || !strings.HasSuffix(msg, "]: multi\nline") {
+		t.Errorf("wrong stream message: %q", output)
+	}
+}
+
+func TestSyslog_unixStream(t *testing.T) {
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Skipf("unix is not supported: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 4096)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _ := conn.Read(buf)
+		received <- string(buf[:n])
+	}()
+
+	s, err := NewSyslog(SyslogConfig{Network: "unix", Address: ln.Addr().String(), RFC3164: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	logger := New(nil)
+	logger.AddSink(s.Sink(Info))
+	logger.Info("multi\nline")
+
+	if output := <-received; !strings.HasSuffix(output, "]: multi line\n") {
+		t.Errorf("stream message must be a single line: %q", output)
+	}
+}
+
+func TestNewSyslog_unavailable(t *testing.T) {
+	_, err := NewSyslog(SyslogConfig{Address: filepath.Join(t.TempDir(), "missing")})
+	if err == nil {
+		t.Error("expected an error for a missing socket")
+	}
+}
@@ -0,0 +1,90 @@
+package logg
+
+import (
+	"log"
+	"sync"
+	"testing"
+)
+
+// testWriter writes messages with t.Log. Messages written after the test
+// has finished are dropped, t.Log panics then.
+type testWriter struct {
+	t testing.TB
+
+	mu   sync.Mutex
+	done bool
+}
+
+func newTestWriter(t testing.TB) *testWriter {
+	w := &testWriter{t: t}
+	t.Cleanup(func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		w.done = true
+	})
+
+	return w
+}
+
+func (w *testWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.done {
+		n := len(b)
+		if n > 0 && b[n-1] == '\n' {
+			n--
+		}
+		w.t.Log(string(b[:n]))
+	}
+
+	return len(b), nil
+}
+
+// NewTest returns a logger which writes messages of all levels with t.Log,
+// so they are attributed to the test and shown only if the test fails
+// or with -v. The file and line which t.Log prints are inside the logger,
+// the caller of a message is written by the Lshortfile flag. Fatal and
+// Panic messages fail the test with t.FailNow instead of exiting or panicking.
+//
+//	func TestService(t *testing.T) {
+//		s := NewService(logg.NewTest(t))
+//	}
+func NewTest(t testing.TB) *Logg {
+	t.Helper()
+
+	l := New(newTestWriter(t))
+	l.flags = Ltime | Lmicroseconds | Lshortfile
+	l.color = false
+	l.minLevel = Trace
+	l.exit = func(int) {
+		t.Helper()
+		t.FailNow()
+	}
+	l.panic = func(string) {
+		t.Helper()
+		t.FailNow()
+	}
+
+	return l
+}
+
+// NewGlobalTest sets a test logger (see NewTest) as the global logger
+// and the output of the standard log package. Both are restored when
+// the test finishes. Tests which use it must not run in parallel.
+func NewGlobalTest(t testing.TB) {
+	t.Helper()
+
+	prev, prevOut, prevFlags := logg.Load(), log.Writer(), log.Flags()
+	t.Cleanup(func() {
+		logg.Store(prev)
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+	})
+
+	l := NewTest(t)
+	logg.Store(l)
+	log.SetOutput(l)
+	log.SetFlags(0)
+}
@@ -0,0 +1,128 @@
+package logg
+
+import (
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingT records logs of a test. FailNow stops the goroutine
+// like testing.T does.
+type recordingT struct {
+	testing.TB
+
+	mu       sync.Mutex
+	logs     []string
+	cleanups []func()
+	failed   bool
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Log(args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.logs = append(t.logs, args[0].(string))
+}
+
+func (t *recordingT) Cleanup(fn func()) { t.cleanups = append(t.cleanups, fn) }
+
+func (t *recordingT) FailNow() {
+	t.mu.Lock()
+	t.failed = true
+	t.mu.Unlock()
+
+	runtime.Goexit()
+}
+
+func (t *recordingT) cleanup() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+// run runs fn in a goroutine and reports whether it returned.
+func (t *recordingT) run(fn func()) bool {
+	returned := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+		returned = true
+	}()
+	<-done
+
+	return returned
+}
+
+func TestNewTest(t *testing.T) {
+	rt := &recordingT{}
+	l := NewTest(rt)
+
+	l.Trace("trace message")
+	l.Infow("info message", "key", "value")
+	l.SetFormat(Json)
+	l.Error("error message")
+
+	if len(rt.logs) != 3 {
+		t.Fatalf("expected 3 logs, received: %v", rt.logs)
+	}
+	for i, s := range []string{"testing_test.go:", "INF info message", `"level": "ERR"`} {
+		if !strings.Contains(rt.logs[i], s) {
+			t.Errorf("log %q must contain %q", rt.logs[i], s)
+		}
+	}
+	if strings.HasSuffix(rt.logs[1], "\n") || !strings.HasSuffix(rt.logs[1], "info message key=value") {
+		t.Errorf("wrong log: %q", rt.logs[1])
+	}
+
+	rt.cleanup()
+	l.Info("after the test")
+	if len(rt.logs) != 3 {
+		t.Errorf("messages after the test must be dropped: %v", rt.logs)
+	}
+}
+
+func TestNewTest_Fatal(t *testing.T) {
+	for name, fn := range map[string]func(l *Logg){
+		"FTL": func(l *Logg) { l.Fatal("message") },
+		"PNC": func(l *Logg) { l.Panicw("message", "key", "value") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			rt := &recordingT{}
+			l := NewTest(rt)
+
+			if rt.run(func() { fn(l) }) {
+				t.Error("the goroutine must be stopped")
+			}
+			if !rt.failed {
+				t.Error("the test must be failed")
+			}
+			if len(rt.logs) != 1 || !strings.Contains(rt.logs[0], name+" message") {
+				t.Errorf("wrong logs: %v", rt.logs)
+			}
+		})
+	}
+}
+
+func TestNewGlobalTest(t *testing.T) {
+	prev, prevOut := logg.Load(), log.Writer()
+
+	rt := &recordingT{}
+	NewGlobalTest(rt)
+
+	log.Print("ERR standard log message")
+	Print("global message")
+
+	if len(rt.logs) != 2 || !strings.Contains(rt.logs[0], "ERR") || !strings.HasSuffix(rt.logs[0], "standard log message") {
+		t.Errorf("wrong logs: %v", rt.logs)
+	}
+
+	rt.cleanup()
+	if logg.Load() != prev || log.Writer() != prevOut {
+		t.Error("global loggers must be restored")
+	}
+}